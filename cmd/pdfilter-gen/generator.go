@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	filterImportPath          = "github.com/Icenium/go-personal-data-filter/filter"
+	generatedFileSuffix       = "_pdfilter.go"
+	personalDataFilterTagName = "pdfilter"
+	noFilterFlagName          = "nofilter"
+	tagConfigSeparator        = ","
+)
+
+var (
+	numericTypeNames = []string{
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune",
+	}
+	nilableTypeNames = []string{"error", "any"}
+)
+
+type generator struct {
+	buf bytes.Buffer
+	// structs contains all struct types declared in the package.
+	structs map[string]*ast.StructType
+	// underlying contains the underlying types of all other types declared in the package.
+	underlying map[string]ast.Expr
+	// generated contains the types which will have generated methods.
+	generated map[string]bool
+}
+
+// generate parses the package in the provided directory and returns the formatted
+// source of the RemovePersonalData methods for the provided types. Only the files matching
+// the current build context are parsed, so the files excluded by build constraints are ignored.
+func generate(dir string, typeNames []string) ([]byte, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		structs:    map[string]*ast.StructType{},
+		underlying: map[string]ast.Expr{},
+		generated:  map[string]bool{},
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range pkg.GoFiles {
+		if strings.HasSuffix(name, generatedFileSuffix) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		g.collectStructs(file)
+	}
+
+	for _, typeName := range typeNames {
+		if _, ok := g.structs[typeName]; !ok {
+			return nil, fmt.Errorf("struct type %s not found in package %s", typeName, pkg.Name)
+		}
+
+		g.generated[typeName] = true
+	}
+
+	g.printf("// Code generated by pdfilter-gen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg.Name)
+	g.printf("import \"%s\"\n", filterImportPath)
+
+	for _, typeName := range typeNames {
+		g.generateMethod(typeName)
+	}
+
+	return format.Source(g.buf.Bytes())
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) collectStructs(file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		if spec.TypeParams != nil {
+			return false
+		}
+
+		if structType, ok := spec.Type.(*ast.StructType); ok {
+			g.structs[spec.Name.Name] = structType
+		} else {
+			g.underlying[spec.Name.Name] = spec.Type
+		}
+
+		return false
+	})
+}
+
+func (g *generator) generateMethod(typeName string) {
+	structType := g.structs[typeName]
+
+	g.printf("\n// RemovePersonalData returns copy of the %s with the personal data removed by the provided filter.\n", typeName)
+	g.printf("func (v %s) RemovePersonalData(f filter.PersonalDataFilter) interface{} {\n", typeName)
+
+	// The reflection-based filter returns the zero values as they are.
+	// We need to do the same in order to produce the same result.
+	if zeroCheck, ok := g.zeroCheck(structType); ok {
+		g.printf("if %s {\nreturn v\n}\n\n", zeroCheck)
+	}
+
+	g.printf("var res %s\n", typeName)
+	for _, field := range structType.Fields.List {
+		if isNoFilterField(field) {
+			for _, name := range fieldNames(field) {
+				if ast.IsExported(name) {
+					g.printf("res.%[1]s = v.%[1]s\n", name)
+				}
+			}
+
+			continue
+		}
+
+		for _, name := range fieldNames(field) {
+			if !ast.IsExported(name) {
+				// The reflection-based filter can't set unexported fields.
+				continue
+			}
+
+			g.generateField(name, field.Type)
+		}
+	}
+
+	g.printf("return res\n}\n")
+}
+
+func (g *generator) generateField(name string, fieldType ast.Expr) {
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		if t.Name == "string" {
+			g.printf("res.%[1]s = filter.FilterProperty(f, %[1]q, v.%[1]s)\n", name)
+			return
+		}
+
+		if t.Name == "bool" || indexOfString(numericTypeNames, t.Name) >= 0 {
+			g.printf("res.%[1]s = v.%[1]s\n", name)
+			return
+		}
+
+		if t.Name == "any" {
+			g.printf("res.%[1]s = f.RemovePersonalData(v.%[1]s)\n", name)
+			return
+		}
+
+		if g.generated[t.Name] {
+			g.printf("res.%[1]s = v.%[1]s.RemovePersonalData(f).(%[2]s)\n", name, t.Name)
+			return
+		}
+
+		if g.isNamedString(t.Name) {
+			g.printf("res.%[1]s = %[2]s(filter.FilterProperty(f, %[1]q, string(v.%[1]s)))\n", name, t.Name)
+			return
+		}
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			g.printf("res.%[1]s = f.RemovePersonalData(v.%[1]s)\n", name)
+			return
+		}
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if ident.Name == "string" {
+				// Pointers to strings are not checked against the personal data properties.
+				g.printf("if v.%[1]s != nil {\nfiltered := filter.FilterString(f, *v.%[1]s)\nres.%[1]s = &filtered\n}\n", name)
+				return
+			}
+
+			if g.generated[ident.Name] {
+				g.printf("if v.%[1]s != nil {\nfiltered := v.%[1]s.RemovePersonalData(f).(%[2]s)\nres.%[1]s = &filtered\n}\n", name, ident.Name)
+				return
+			}
+		}
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil {
+			var filterItem string
+			if ident.Name == "string" {
+				filterItem = "filter.FilterString(f, item)"
+			} else if g.generated[ident.Name] {
+				filterItem = fmt.Sprintf("item.RemovePersonalData(f).(%s)", ident.Name)
+			}
+
+			if filterItem != "" {
+				g.printf("if v.%[1]s != nil {\nres.%[1]s = make(%[2]s, len(v.%[1]s))\nfor i, item := range v.%[1]s {\nres.%[1]s[i] = %[3]s\n}\n}\n",
+					name, types.ExprString(t), filterItem)
+				return
+			}
+		}
+	}
+
+	// The reflection-based filter checks the static type of the fields, so the text values of the interface fields
+	// are not masked. The type assertion will fail only for nil interfaces.
+	if g.isInterface(fieldType) {
+		g.printf("res.%[1]s, _ = f.RemovePersonalData(v.%[1]s).(%[2]s)\n", name, types.ExprString(fieldType))
+		return
+	}
+
+	// Fall back to the filter for all other types, including the maps whose keys may be filtered with the collision
+	// policy of the filter and the byte slices which are masked when the field is personal data property.
+	g.printf("res.%[1]s, _ = filter.FilterPropertyValue(f, %[1]q, v.%[1]s).(%[2]s)\n", name, types.ExprString(fieldType))
+}
+
+// isNamedString reports whether the type is declared in the package with string as underlying type.
+func (g *generator) isNamedString(typeName string) bool {
+	for visited := map[string]bool{}; !visited[typeName]; {
+		visited[typeName] = true
+		ident, ok := g.underlying[typeName].(*ast.Ident)
+		if !ok {
+			return false
+		}
+
+		if ident.Name == "string" {
+			return true
+		}
+
+		typeName = ident.Name
+	}
+
+	return false
+}
+
+// isInterface reports whether the type is interface type. The types declared in other packages are not known
+// without type checking, so they are considered interfaces only if they are the predeclared error type.
+func (g *generator) isInterface(fieldType ast.Expr) bool {
+	for visited := map[string]bool{}; ; {
+		switch t := fieldType.(type) {
+		case *ast.InterfaceType:
+			return true
+		case *ast.Ident:
+			if t.Name == "error" || t.Name == "any" {
+				return true
+			}
+
+			if visited[t.Name] || g.underlying[t.Name] == nil {
+				return false
+			}
+
+			visited[t.Name] = true
+			fieldType = g.underlying[t.Name]
+		default:
+			return false
+		}
+	}
+}
+
+// zeroCheck returns expression which checks if the struct is its zero value.
+// It returns false if such expression can't be created for some of the field types.
+func (g *generator) zeroCheck(structType *ast.StructType) (string, bool) {
+	checks := []string{}
+	for _, field := range structType.Fields.List {
+		for _, name := range fieldNames(field) {
+			check, ok := g.fieldZeroCheck("v."+name, field.Type)
+			if !ok {
+				return "", false
+			}
+
+			checks = append(checks, check)
+		}
+	}
+
+	if len(checks) == 0 {
+		return "true", true
+	}
+
+	return strings.Join(checks, " && "), true
+}
+
+func (g *generator) fieldZeroCheck(value string, fieldType ast.Expr) (string, bool) {
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		switch {
+		case t.Name == "string":
+			return value + ` == ""`, true
+		case t.Name == "bool":
+			return "!" + value, true
+		case indexOfString(numericTypeNames, t.Name) >= 0:
+			return value + " == 0", true
+		case indexOfString(nilableTypeNames, t.Name) >= 0:
+			return value + " == nil", true
+		case g.structs[t.Name] != nil && g.isComparable(t, map[string]bool{}):
+			return fmt.Sprintf("%s == (%s{})", value, t.Name), true
+		case g.isNamedString(t.Name):
+			return value + ` == ""`, true
+		case g.underlying[t.Name] != nil && !g.isDeclared(g.underlying[t.Name]):
+			return g.fieldZeroCheck(value, g.underlying[t.Name])
+		}
+	case *ast.StarExpr, *ast.MapType, *ast.FuncType, *ast.ChanType, *ast.InterfaceType:
+		return value + " == nil", true
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return value + " == nil", true
+		}
+	}
+
+	return "", false
+}
+
+// isDeclared reports whether the type is declared in the package.
+func (g *generator) isDeclared(fieldType ast.Expr) bool {
+	ident, ok := fieldType.(*ast.Ident)
+	return ok && (g.structs[ident.Name] != nil || g.underlying[ident.Name] != nil)
+}
+
+// isComparable reports whether the values of the type can be compared with == without panic.
+func (g *generator) isComparable(fieldType ast.Expr, visited map[string]bool) bool {
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		if t.Name == "string" || t.Name == "bool" || indexOfString(numericTypeNames, t.Name) >= 0 {
+			return true
+		}
+
+		if underlying := g.underlying[t.Name]; underlying != nil && !g.isDeclared(underlying) {
+			return g.isComparable(underlying, visited)
+		}
+
+		structType := g.structs[t.Name]
+		if structType == nil || visited[t.Name] {
+			return false
+		}
+
+		visited[t.Name] = true
+		for _, field := range structType.Fields.List {
+			if !g.isComparable(field.Type, visited) {
+				return false
+			}
+		}
+
+		return true
+	case *ast.StarExpr, *ast.ChanType:
+		return true
+	case *ast.ArrayType:
+		return t.Len != nil && g.isComparable(t.Elt, visited)
+	default:
+		return false
+	}
+}
+
+func isNoFilterField(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+
+	config, ok := reflect.StructTag(tag).Lookup(personalDataFilterTagName)
+	if !ok {
+		return false
+	}
+
+	return indexOfString(strings.Split(config, tagConfigSeparator), noFilterFlagName) >= 0
+}
+
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := []string{}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		return names
+	}
+
+	// The name of the embedded field is the name of its type.
+	fieldType := field.Type
+	if star, ok := fieldType.(*ast.StarExpr); ok {
+		fieldType = star.X
+	}
+
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	default:
+		return nil
+	}
+}
+
+func indexOfString(collection []string, value string) int {
+	for i, v := range collection {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// The generated code is compiled and tested against the reflection-based filter in the fixtures package.
+var fixturesDir = filepath.Join("internal", "fixtures")
+
+func TestGenerate(t *testing.T) {
+	Convey("generate", t, func() {
+		Convey("Should generate methods for the provided types.", func() {
+			expected, err := ioutil.ReadFile(filepath.Join(fixturesDir, "customer_pdfilter.go"))
+			So(err, ShouldBeNil)
+
+			src, err := generate(fixturesDir, []string{"Address", "Customer", "Order"})
+
+			So(err, ShouldBeNil)
+			So(string(src), ShouldEqual, string(expected))
+		})
+
+		Convey("Should fail if the type is not declared in the package.", func() {
+			_, err := generate(fixturesDir, []string{"Missing"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should ignore the files excluded by build constraints.", func() {
+			dir, err := ioutil.TempDir("", "pdfilter-gen")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			files := map[string]string{
+				"user.go":    "package users\n\ntype User struct {\n\tEmail string\n}\n",
+				"ignored.go": "//go:build ignore\n\npackage main\n\ntype User struct {\n\tName string\n}\n",
+			}
+			for name, content := range files {
+				So(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644), ShouldBeNil)
+			}
+
+			src, err := generate(dir, []string{"User"})
+
+			So(err, ShouldBeNil)
+			So(string(src), ShouldContainSubstring, "package users")
+			So(string(src), ShouldContainSubstring, `res.Email = filter.FilterProperty(f, "Email", v.Email)`)
+			So(string(src), ShouldNotContainSubstring, "v.Name")
+		})
+	})
+}
@@ -0,0 +1,38 @@
+// Package fixtures contains the types used to test the code generated by pdfilter-gen.
+// The generated methods are compiled with the package and compared with the reflection-based filter.
+package fixtures
+
+//go:generate go run github.com/Icenium/go-personal-data-filter/cmd/pdfilter-gen -type=Address,Customer,Order -output=customer_pdfilter.go
+
+// Secret is text type which is filtered like string.
+type Secret string
+
+type Address struct {
+	City   string
+	Street string
+	Number int
+}
+
+type Customer struct {
+	Email     string
+	Name      string
+	ID        string `pdfilter:"nofilter"`
+	Age       int
+	Active    bool
+	Nickname  *string
+	Tags      []string
+	Labels    map[string]string
+	Address   Address
+	Previous  *Address
+	Addresses []Address
+	Extra     interface{}
+	Password  []byte
+	Account   Secret
+	Comment   Secret
+	notes     string
+}
+
+type Order struct {
+	Customer
+	Items []map[string]interface{}
+}
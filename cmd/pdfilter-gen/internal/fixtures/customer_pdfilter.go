@@ -0,0 +1,67 @@
+// Code generated by pdfilter-gen. DO NOT EDIT.
+
+package fixtures
+
+import "github.com/Icenium/go-personal-data-filter/filter"
+
+// RemovePersonalData returns copy of the Address with the personal data removed by the provided filter.
+func (v Address) RemovePersonalData(f filter.PersonalDataFilter) interface{} {
+	if v.City == "" && v.Street == "" && v.Number == 0 {
+		return v
+	}
+
+	var res Address
+	res.City = filter.FilterProperty(f, "City", v.City)
+	res.Street = filter.FilterProperty(f, "Street", v.Street)
+	res.Number = v.Number
+	return res
+}
+
+// RemovePersonalData returns copy of the Customer with the personal data removed by the provided filter.
+func (v Customer) RemovePersonalData(f filter.PersonalDataFilter) interface{} {
+	if v.Email == "" && v.Name == "" && v.ID == "" && v.Age == 0 && !v.Active && v.Nickname == nil && v.Tags == nil && v.Labels == nil && v.Address == (Address{}) && v.Previous == nil && v.Addresses == nil && v.Extra == nil && v.Password == nil && v.Account == "" && v.Comment == "" && v.notes == "" {
+		return v
+	}
+
+	var res Customer
+	res.Email = filter.FilterProperty(f, "Email", v.Email)
+	res.Name = filter.FilterProperty(f, "Name", v.Name)
+	res.ID = v.ID
+	res.Age = v.Age
+	res.Active = v.Active
+	if v.Nickname != nil {
+		filtered := filter.FilterString(f, *v.Nickname)
+		res.Nickname = &filtered
+	}
+	if v.Tags != nil {
+		res.Tags = make([]string, len(v.Tags))
+		for i, item := range v.Tags {
+			res.Tags[i] = filter.FilterString(f, item)
+		}
+	}
+	res.Labels, _ = filter.FilterPropertyValue(f, "Labels", v.Labels).(map[string]string)
+	res.Address = v.Address.RemovePersonalData(f).(Address)
+	if v.Previous != nil {
+		filtered := v.Previous.RemovePersonalData(f).(Address)
+		res.Previous = &filtered
+	}
+	if v.Addresses != nil {
+		res.Addresses = make([]Address, len(v.Addresses))
+		for i, item := range v.Addresses {
+			res.Addresses[i] = item.RemovePersonalData(f).(Address)
+		}
+	}
+	res.Extra = f.RemovePersonalData(v.Extra)
+	res.Password, _ = filter.FilterPropertyValue(f, "Password", v.Password).([]byte)
+	res.Account = Secret(filter.FilterProperty(f, "Account", string(v.Account)))
+	res.Comment = Secret(filter.FilterProperty(f, "Comment", string(v.Comment)))
+	return res
+}
+
+// RemovePersonalData returns copy of the Order with the personal data removed by the provided filter.
+func (v Order) RemovePersonalData(f filter.PersonalDataFilter) interface{} {
+	var res Order
+	res.Customer = v.Customer.RemovePersonalData(f).(Customer)
+	res.Items, _ = filter.FilterPropertyValue(f, "Items", v.Items).([]map[string]interface{})
+	return res
+}
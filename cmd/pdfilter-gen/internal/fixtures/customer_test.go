@@ -0,0 +1,84 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// The types without methods are filtered with reflection. The fields of reflectedCustomer still use the generated
+// methods, so the nested types are compared on their own.
+type (
+	reflectedAddress  Address
+	reflectedCustomer Customer
+	reflectedOrder    Order
+)
+
+func TestGeneratedMethods(t *testing.T) {
+	Convey("Generated RemovePersonalData methods", t, func() {
+		email := "user@mail.com"
+		nickname := "nick " + email
+		address := Address{City: "Sofia " + email, Street: "192.168.0.1 street", Number: 5}
+		customer := Customer{
+			Email:     email,
+			Name:      "name " + email,
+			ID:        email,
+			Age:       42,
+			Active:    true,
+			Nickname:  &nickname,
+			Tags:      []string{"tag", email},
+			Labels:    map[string]string{email: "value", "user@other.com": email, "plain": "192.168.0.2"},
+			Address:   address,
+			Previous:  &address,
+			Addresses: []Address{address, {}},
+			Extra:     map[string]interface{}{"email": "value", "nested": []string{email}},
+			Password:  []byte("not-personal"),
+			Account:   "not-personal",
+			Comment:   Secret("from " + email),
+			notes:     email,
+		}
+		order := Order{
+			Customer: customer,
+			Items:    []map[string]interface{}{{"username": "name", email: 1}, nil},
+		}
+
+		filters := map[string]*filter.PersonalDataFilterBuilder{
+			"default":              filter.NewBuilder(),
+			"mask and properties":  filter.NewBuilder().SetMask("[redacted]").AddPersonalDataProperties("city", "name"),
+			"map keys with suffix": filter.NewBuilder().FilterMapKeys(filter.MapKeyCollisionSuffix),
+			"map keys keep first":  filter.NewBuilder().FilterMapKeys(filter.MapKeyCollisionKeepFirst),
+		}
+
+		for name, builder := range filters {
+			f, err := builder.Build()
+			So(err, ShouldBeNil)
+
+			Convey("Should produce the same result as reflection with "+name+" filter.", func() {
+				for _, input := range []Address{address, {}, {Number: 1}} {
+					So(input.RemovePersonalData(f), ShouldResemble, Address(f.RemovePersonalData(reflectedAddress(input)).(reflectedAddress)))
+				}
+
+				for _, input := range []Customer{customer, {}, {notes: email}, {Labels: map[string]string{}}, {Password: []byte{}}, {Comment: "text"}} {
+					So(input.RemovePersonalData(f), ShouldResemble, Customer(f.RemovePersonalData(reflectedCustomer(input)).(reflectedCustomer)))
+				}
+
+				for _, input := range []Order{order, {}} {
+					So(input.RemovePersonalData(f), ShouldResemble, Order(f.RemovePersonalData(reflectedOrder(input)).(reflectedOrder)))
+				}
+			})
+
+			Convey("Should mask the text values of the personal data properties with "+name+" filter.", func() {
+				filtered := customer.RemovePersonalData(f).(Customer)
+				So(filtered.Password, ShouldResemble, []byte(filter.Mask(f)))
+				So(filtered.Account, ShouldEqual, Secret(filter.Mask(f)))
+				So(filtered.Comment, ShouldEqual, Secret(filter.FilterString(f, string(customer.Comment))))
+			})
+
+			Convey("Should be used by the filter with "+name+" filter.", func() {
+				So(f.RemovePersonalData(order), ShouldResemble, order.RemovePersonalData(f))
+				So(f.RemovePersonalData(&customer), ShouldResemble, &[]Customer{customer.RemovePersonalData(f).(Customer)}[0])
+			})
+		}
+	})
+}
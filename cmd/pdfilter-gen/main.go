@@ -0,0 +1,79 @@
+// Pdfilter-gen is a tool to automate the creation of reflection-free RemovePersonalData methods.
+// Given the name of one or more struct types T, pdfilter-gen will create a new self-contained
+// Go source file implementing
+//
+//	func (v T) RemovePersonalData(f filter.PersonalDataFilter) interface{}
+//
+// The generated methods use the regular expressions and the personal data properties of the
+// filter they receive, so they respect the configuration created with filter.PersonalDataFilterBuilder.
+// The filter's RemovePersonalData method uses the generated methods instead of reflection.
+//
+// The file is created in the same package and directory as the package that defines T.
+// It has the same name as the first type in lower case with _pdfilter.go suffix.
+// The default output file can be overridden with the -output flag.
+//
+// The pdfilter struct tags are respected. Fields with `pdfilter:"nofilter"` are copied as they are.
+// The unexported fields are set to their zero values, exactly like the reflection-based filter does.
+// The string fields, including the fields of named string types, are filtered with filter.FilterProperty.
+// The maps, the byte slices and the other fields which can't be filtered without reflection are passed to
+// filter.FilterPropertyValue, so their keys are filtered with the configured collision policy and the text values
+// of the personal data properties are masked.
+//
+// Typically this process would be run using go generate, like this:
+//
+//	//go:generate pdfilter-gen -type=Customer,Order
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_pdfilter.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of pdfilter-gen:\n")
+	fmt.Fprintf(os.Stderr, "\tpdfilter-gen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("pdfilter-gen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if len(*typeNames) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	types := strings.Split(*typeNames, ",")
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+generatedFileSuffix)
+	}
+
+	src, err := generate(dir, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+}
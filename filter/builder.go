@@ -22,9 +22,12 @@ const (
 
 var (
 	personalDataProperties = []string{"email", "useremail", "user", "username", "userid", "accountid", "account", "password", "pass", "pwd", "ip", "ipaddress"}
+	sensitiveHeaders       = []string{"authorization", "proxy-authorization", "x-forwarded-for", "x-real-ip", "forwarded", "x-api-key", "x-auth-token"}
 
 	errRegExpAndAdditionalRegExp   = errors.New("can't use AddRegularExpressions and SetRegExp at the same time")
 	errPDPropsAndAdditionalPDProps = errors.New("can't use SetPersonalDataProperties and AddPersonalDataProperties at the same time")
+	errInvalidWorkersCount         = errors.New("the number of workers must be positive")
+	errHeadersAndAdditionalHeaders = errors.New("can't use SetSensitiveHeaders and AddSensitiveHeaders at the same time")
 )
 
 // PersonalDataFilterBuilder builds personal data filter
@@ -36,6 +39,13 @@ type PersonalDataFilterBuilder struct {
 	personalDataProperties           []string
 	additionalPersonalDataProperties []string
 	matchFilterFunc                  *MatchFilterFunc
+	mapKeyCollisionPolicy            *MapKeyCollisionPolicy
+	filterURLs                       bool
+	sensitiveHeaders                 []string
+	additionalSensitiveHeaders       []string
+	sensitiveCookies                 []string
+	workers                          int
+	minCollectionSize                int
 	err                              error
 }
 
@@ -130,6 +140,89 @@ func (b *PersonalDataFilterBuilder) UseDefaultMatchFilterFunc() *PersonalDataFil
 	return b.SetMatchFilterFunc(defaultFilterFunction)
 }
 
+// FilterMapKeys enables the filtering of map keys. The regular expressions will be applied to string keys and
+// keys implementing encoding.TextMarshaler. The policy defines what happens when two keys become equal after filtering.
+// The entries with encoding.TextMarshaler keys which can't be restored from the filtered text will be removed.
+func (b *PersonalDataFilterBuilder) FilterMapKeys(policy MapKeyCollisionPolicy) *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.mapKeyCollisionPolicy = &policy
+	return b
+}
+
+// FilterURLs enables the URL-aware filtering of strings. The URLs found in the strings are parsed. The user info is
+// replaced with the mask, the values of the query and fragment parameters with personal data property names are
+// replaced with the mask and the host, the path segments and the other parameters are filtered with the regular expressions.
+func (b *PersonalDataFilterBuilder) FilterURLs() *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.filterURLs = true
+	return b
+}
+
+// SetSensitiveHeaders sets the names of the HTTP headers which values will be replaced with the mask.
+func (b *PersonalDataFilterBuilder) SetSensitiveHeaders(names ...string) *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(b.additionalSensitiveHeaders) > 0 {
+		b.err = errHeadersAndAdditionalHeaders
+		return b
+	}
+
+	b.sensitiveHeaders = names
+	return b
+}
+
+// AddSensitiveHeaders adds names of HTTP headers to the default sensitive ones. The values of these
+// headers will be replaced with the mask.
+func (b *PersonalDataFilterBuilder) AddSensitiveHeaders(names ...string) *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(b.sensitiveHeaders) > 0 {
+		b.err = errHeadersAndAdditionalHeaders
+		return b
+	}
+
+	b.additionalSensitiveHeaders = names
+	return b
+}
+
+// SetSensitiveCookies sets the names of the cookies which values will be replaced with the mask. The values of the
+// other cookies will be filtered with the regular expressions. By default the values of all cookies are replaced.
+func (b *PersonalDataFilterBuilder) SetSensitiveCookies(names ...string) *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.sensitiveCookies = names
+	return b
+}
+
+// SetConcurrency enables concurrent filtering of the slices, arrays and maps which have at least minCollectionSize items.
+// The workers is the maximum number of goroutines which will filter collections at the same time. The items keep their order.
+func (b *PersonalDataFilterBuilder) SetConcurrency(workers, minCollectionSize int) *PersonalDataFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if workers < 1 {
+		b.err = errInvalidWorkersCount
+		return b
+	}
+
+	b.workers = workers
+	b.minCollectionSize = minCollectionSize
+	return b
+}
+
 // Build creates new personal data filter from the provided configuration.
 func (b *PersonalDataFilterBuilder) Build() (PersonalDataFilter, error) {
 	if b.err != nil {
@@ -170,6 +263,30 @@ func (b *PersonalDataFilterBuilder) Build() (PersonalDataFilter, error) {
 	}
 
 	res.matchFilterFunc = b.matchFilterFunc
+	res.mapKeyCollisionPolicy = b.mapKeyCollisionPolicy
+	res.filterURLs = b.filterURLs
+
+	// Handle HTTP config.
+	headers := b.sensitiveHeaders
+	if len(headers) == 0 {
+		headers = append(sensitiveHeaders, b.additionalSensitiveHeaders...)
+	}
+
+	for _, h := range headers {
+		res.sensitiveHeaders = append(res.sensitiveHeaders, strings.ToLower(h))
+	}
+
+	res.sensitiveCookies = b.sensitiveCookies
+
+	// Handle concurrency config.
+	if b.workers > 0 {
+		res.concurrency = &concurrencyConfig{
+			minCollectionSize: b.minCollectionSize,
+			// The goroutine which calls the filter is one of the workers.
+			workerTokens: make(chan struct{}, b.workers-1),
+		}
+	}
+
 	return res, nil
 }
 
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	byteType       = reflect.TypeOf(byte(0))
+	bufferType     = reflect.TypeOf(bytes.Buffer{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// filterBytes replaces the personal data found by the regular expressions in the provided bytes.
+// It returns a copy of the input.
+func (filter *personalDataFilter) filterBytes(input []byte) []byte {
+	if filter.filterURLs {
+		return []byte(filter.filterURLsInString(string(input)))
+	}
+
+	if filter.matchFilterFunc != nil {
+		return filter.personalDataRegExp.ReplaceAllFunc(input, func(match []byte) []byte {
+			return []byte((*filter.matchFilterFunc)(string(match)))
+		})
+	}
+
+	return filter.personalDataRegExp.ReplaceAll(input, []byte(filter.mask))
+}
+
+func (filter *personalDataFilter) handleBytes(input interface{}) interface{} {
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Type() == rawMessageType {
+		return filter.handleRawMessage(input.(json.RawMessage))
+	}
+
+	filtered := filter.filterBytes(inputValue.Bytes())
+
+	// The input may be of a named []byte type. We need to return value of the same type.
+	return reflect.ValueOf(filtered).Convert(inputValue.Type()).Interface()
+}
+
+// handleRawMessage filters the embedded JSON structurally. The raw message is filtered as text
+// if it is not a valid JSON.
+func (filter *personalDataFilter) handleRawMessage(input json.RawMessage) interface{} {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	// We need the numbers as they are. The float64 values may lose precision.
+	decoder.UseNumber()
+	if !json.Valid(input) || decoder.Decode(&value) != nil {
+		return json.RawMessage(filter.filterBytes(input))
+	}
+
+	filtered, err := marshalJSON(filter.RemovePersonalData(value))
+	if err != nil {
+		return json.RawMessage(filter.filterBytes(input))
+	}
+
+	return json.RawMessage(filtered)
+}
+
+func (filter *personalDataFilter) handleBuffer(input interface{}) interface{} {
+	buffer := input.(bytes.Buffer)
+	return *bytes.NewBuffer(filter.filterBytes(buffer.Bytes()))
+}
+
+// maskOf returns the mask as value of the same type as the provided one.
+func (filter *personalDataFilter) maskOf(value reflect.Value) interface{} {
+	return maskValue(filter.mask, value)
+}
+
+// maskValue converts the mask to value of the same type as the provided text value.
+func maskValue(mask string, value reflect.Value) interface{} {
+	switch {
+	case value.Type() == rawMessageType:
+		// The mask must be valid JSON.
+		encoded, _ := marshalJSON(mask)
+		return json.RawMessage(encoded)
+	case value.Type() == bufferType:
+		return *bytes.NewBufferString(mask)
+	case isBytesType(value.Type()):
+		return reflect.ValueOf([]byte(mask)).Convert(value.Type()).Interface()
+	default:
+		return reflect.ValueOf(mask).Convert(value.Type()).Interface()
+	}
+}
+
+// isTextValue checks if the value should be treated as text.
+func isTextValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return false
+	}
+
+	return value.Kind() == reflect.String || isBytesType(value.Type()) || value.Type() == bufferType
+}
+
+func isBytesType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem() == byteType
+}
+
+// marshalJSON marshals the value without escaping the HTML characters.
+func marshalJSON(value interface{}) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	// The encoder adds new line after each value.
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
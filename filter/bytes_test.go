@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type namedBytes []byte
+
+func TestPersonalDataFilterBytes(t *testing.T) {
+	Convey("Bytes", t, func() {
+		email := "some@mail.com"
+		notPersonalDataString := "not-personal"
+
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		if err != nil {
+			panic(err)
+		}
+
+		Convey("Should filter []byte as text", func() {
+			result := filter.RemovePersonalData([]byte("text " + email + " text"))
+			So(result, ShouldResemble, []byte("text "+filteredString+" text"))
+
+			result = filter.RemovePersonalData(namedBytes(email))
+			So(result, ShouldResemble, namedBytes(filteredString))
+		})
+
+		Convey("Should use the match filter function", func() {
+			hashFilter, _ := NewBuilder().UseDefaultMatchFilterFunc().Build()
+			result := hashFilter.RemovePersonalData([]byte(email))
+			So(result, ShouldResemble, []byte(getHash(email)))
+		})
+
+		Convey("Should filter bytes.Buffer as text", func() {
+			result := filter.RemovePersonalData(*bytes.NewBufferString(email)).(bytes.Buffer)
+			So(result.String(), ShouldEqual, filteredString)
+
+			pointerResult := filter.RemovePersonalData(bytes.NewBufferString(email))
+			So(pointerResult.(*bytes.Buffer).String(), ShouldEqual, filteredString)
+		})
+
+		Convey("Should filter json.RawMessage structurally", func() {
+			input := json.RawMessage(`{"email":"not-personal","items":["` + email + `",12345678901234567890],"notPersonal":"<not-personal>"}`)
+			result := filter.RemovePersonalData(input)
+			So(string(result.(json.RawMessage)), ShouldEqual, `{"email":"*****","items":["*****",12345678901234567890],"notPersonal":"<not-personal>"}`)
+		})
+
+		Convey("Should filter invalid json.RawMessage as text", func() {
+			result := filter.RemovePersonalData(json.RawMessage(`{"email":"` + email))
+			So(string(result.(json.RawMessage)), ShouldEqual, `{"email":"`+filteredString)
+		})
+
+		Convey("Should mask the personal data properties", func() {
+			type bytesStruct struct {
+				Password    []byte
+				Email       json.RawMessage
+				User        bytes.Buffer
+				NotPersonal []byte
+			}
+
+			input := bytesStruct{
+				Password:    []byte(notPersonalDataString),
+				Email:       json.RawMessage(`"` + notPersonalDataString + `"`),
+				User:        *bytes.NewBufferString(notPersonalDataString),
+				NotPersonal: []byte(notPersonalDataString),
+			}
+
+			result := filter.RemovePersonalData(input).(bytesStruct)
+			So(result.Password, ShouldResemble, []byte(filteredString))
+			So(string(result.Email), ShouldEqual, `"`+filteredString+`"`)
+			So(result.User.String(), ShouldEqual, filteredString)
+			So(result.NotPersonal, ShouldResemble, []byte(notPersonalDataString))
+
+			mapResult := filter.RemovePersonalData(map[string][]byte{"password": []byte(notPersonalDataString)})
+			So(mapResult, ShouldResemble, map[string][]byte{"password": []byte(filteredString)})
+		})
+	})
+}
@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type concurrencyConfig struct {
+	minCollectionSize int
+	// workerTokens limits the number of additional goroutines used by the filter.
+	// It is shared by all calls, so the nested collections can't multiply the number of goroutines.
+	workerTokens chan struct{}
+}
+
+// RemovePersonalDataContext removes the personal data from the provided input. It stops filtering and returns the
+// error of the context when the context is canceled. The filters which are not created by PersonalDataFilterBuilder
+// can't be stopped, so the context is checked only before and after filtering.
+func RemovePersonalDataContext(ctx context.Context, filter PersonalDataFilter, input interface{}) (interface{}, error) {
+	if built, err := builtFilter(filter); err == nil {
+		return built.removePersonalDataContext(ctx, input)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res := filter.RemovePersonalData(input)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (filter *personalDataFilter) removePersonalDataContext(ctx context.Context, input interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filterCopy := *filter
+	filterCopy.ctx = ctx
+	res := filterCopy.RemovePersonalData(input)
+
+	// The result may be partially filtered if the context was canceled.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (filter *personalDataFilter) isCanceled() bool {
+	return filter.ctx != nil && filter.ctx.Err() != nil
+}
+
+// forEachIndex calls the function for each index in [0, length). The function is called concurrently
+// when the concurrency is enabled and the length is not less than the configured minimum collection size.
+// It stops calling the function when the context of the filter is canceled.
+func (filter *personalDataFilter) forEachIndex(length int, fn func(i int)) {
+	if filter.concurrency == nil || length < filter.concurrency.minCollectionSize {
+		for i := 0; i < length && !filter.isCanceled(); i++ {
+			fn(i)
+		}
+
+		return
+	}
+
+	var next int64
+	work := func() {
+		for !filter.isCanceled() {
+			i := int(atomic.AddInt64(&next, 1) - 1)
+			if i >= length {
+				return
+			}
+
+			fn(i)
+		}
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// The current goroutine works too. Additional goroutines are started only while there are free tokens.
+	// We don't wait for tokens, because the nested collections would deadlock.
+	for started := 0; started < cap(filter.concurrency.workerTokens); started++ {
+		select {
+		case filter.concurrency.workerTokens <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-filter.concurrency.workerTokens
+					wg.Done()
+				}()
+				work()
+			}()
+		default:
+			work()
+			return
+		}
+	}
+
+	work()
+}
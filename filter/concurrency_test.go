@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type cancelingStruct struct {
+	cancel context.CancelFunc
+}
+
+func (v cancelingStruct) RedactPersonalData(f PersonalDataFilter) interface{} {
+	v.cancel()
+	return v
+}
+
+func TestPersonalDataFilterConcurrency(t *testing.T) {
+	Convey("Concurrency", t, func() {
+		email := "some@mail.com"
+		nStruct := nestedStruct{AccountID: "personal-data", IP: "192.168.0.1", NotPersonal: email}
+
+		sequential, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		concurrent, err := NewBuilder().SetMask(filteredString).SetConcurrency(4, 10).Build()
+		So(err, ShouldBeNil)
+
+		slice := []interface{}{}
+		nestedSlices := [][]nestedStruct{}
+		mapInput := map[string]interface{}{}
+		for i := 0; i < 1000; i++ {
+			slice = append(slice, fmt.Sprintf("%d %s", i, email), nStruct, i)
+			nestedSlices = append(nestedSlices, []nestedStruct{nStruct, nStruct, nStruct, nStruct, nStruct, nStruct, nStruct, nStruct, nStruct, nStruct, nStruct})
+			mapInput[fmt.Sprintf("key%d", i)] = fmt.Sprintf("%d %s", i, email)
+			mapInput[fmt.Sprintf("email%d", i)] = nStruct
+		}
+
+		mapInput["email"] = "not-personal"
+
+		var array [100]string
+		for i := range array {
+			array[i] = fmt.Sprintf("%d %s", i, email)
+		}
+
+		Convey("Should produce the same result as the sequential filtering", func() {
+			for _, input := range []interface{}{slice, &slice, nestedSlices, mapInput, array} {
+				So(concurrent.RemovePersonalData(input), ShouldResemble, sequential.RemovePersonalData(input))
+			}
+		})
+
+		Convey("Should filter the small collections sequentially", func() {
+			small := []string{email}
+			So(concurrent.RemovePersonalData(small), ShouldResemble, []string{filteredString})
+		})
+
+		Convey("RemovePersonalDataContext", func() {
+			Convey("Should filter the input", func() {
+				result, err := RemovePersonalDataContext(context.Background(), concurrent, slice)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, sequential.RemovePersonalData(slice))
+			})
+
+			Convey("Should return error when the context is canceled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result, err := RemovePersonalDataContext(ctx, sequential, slice)
+				So(result, ShouldBeNil)
+				So(err, ShouldEqual, context.Canceled)
+			})
+
+			Convey("Should stop filtering when the context is canceled", func() {
+				for _, f := range []PersonalDataFilter{sequential, concurrent} {
+					ctx, cancel := context.WithCancel(context.Background())
+					input := append([]interface{}{cancelingStruct{cancel: cancel}}, slice...)
+
+					result, err := RemovePersonalDataContext(ctx, f, input)
+					So(result, ShouldBeNil)
+					So(err, ShouldEqual, context.Canceled)
+				}
+			})
+		})
+
+		Convey("Should fail the build if the number of workers is not positive.", func() {
+			_, err := NewBuilder().SetConcurrency(0, 10).Build()
+			So(err, ShouldBeError, errInvalidWorkersCount)
+		})
+
+		Convey("Should not set the concurrency if there is builder error.", func() {
+			b := NewBuilder()
+			b.err = errPDPropsAndAdditionalPDProps
+			b = b.SetConcurrency(4, 10)
+			So(b.workers, ShouldEqual, 0)
+		})
+	})
+}
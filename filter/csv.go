@@ -0,0 +1,73 @@
+package filter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// FilterCSV reads CSV from the reader and writes it filtered to the writer row by row. The columns with
+// personal data property names in the header and the columns from the options are replaced with the mask.
+// All other cells are filtered with the regular expressions.
+func FilterCSV(filter PersonalDataFilter, r io.Reader, w io.Writer, options CSVOptions) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterCSV(r, w, options)
+}
+
+func (filter *personalDataFilter) filterCSV(r io.Reader, w io.Writer, options CSVOptions) error {
+	reader := csv.NewReader(r)
+	writer := csv.NewWriter(w)
+	if options.Comma != 0 {
+		reader.Comma = options.Comma
+		writer.Comma = options.Comma
+	}
+
+	// The rows may have different number of fields. Each row is written before the next one is read,
+	// so the record can be reused.
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	personalDataColumns := map[int]bool{}
+	for _, column := range options.PersonalDataColumns {
+		personalDataColumns[column] = true
+	}
+
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if row == 0 && !options.NoHeader {
+			for i, name := range record {
+				if filter.IsPersonalDataProperty(name) {
+					personalDataColumns[i] = true
+				}
+
+				record[i] = filter.FilterString(name)
+			}
+		} else {
+			for i, cell := range record {
+				if personalDataColumns[i] {
+					record[i] = filter.mask
+				} else {
+					record[i] = filter.FilterString(cell)
+				}
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
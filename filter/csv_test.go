@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterCSV(t *testing.T) {
+	Convey("FilterCSV", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		filterCSV := func(input string, options CSVOptions) (string, error) {
+			output := new(bytes.Buffer)
+			err := FilterCSV(filter, strings.NewReader(input), output, options)
+			return output.String(), err
+		}
+
+		Convey("Should mask the personal data columns from the header", func() {
+			input := "id,Email,notes,UserName\n" +
+				"1,not-personal,\"contact some@mail.com, or call\",john\n" +
+				"2,,192.168.0.1\n"
+
+			result, err := filterCSV(input, CSVOptions{})
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "id,Email,notes,UserName\n"+
+				"1,*****,\"contact *****, or call\",*****\n"+
+				"2,*****,*****\n")
+		})
+
+		Convey("Should support TSV and headerless files with column rules", func() {
+			input := "1\tjohn\tsome@mail.com\n2\tjane\tnot-personal\n"
+
+			result, err := filterCSV(input, CSVOptions{Comma: '\t', NoHeader: true, PersonalDataColumns: []int{1}})
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "1\t*****\t*****\n2\t*****\tnot-personal\n")
+		})
+
+		Convey("Should return error for invalid CSV", func() {
+			_, err := filterCSV("a,\"b\n", CSVOptions{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package filter
+
+// redactedError filters the text of the wrapped error. The wrapped error is returned by Unwrap,
+// so errors.Is and errors.As work with the original chain.
+type redactedError struct {
+	filter PersonalDataFilter
+	err    error
+}
+
+func (e *redactedError) Error() string {
+	return FilterString(e.filter, e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactedJoinError filters the text of error created by errors.Join or another error with Unwrap() []error method.
+// Unwrap returns the wrapped children, whose text is filtered. They unwrap to the original errors,
+// so errors.Is and errors.As still find them, and the errors found by errors.As are not filtered.
+type redactedJoinError struct {
+	filter PersonalDataFilter
+	err    error
+	errs   []error
+}
+
+func (e *redactedJoinError) Error() string {
+	return FilterString(e.filter, e.err.Error())
+}
+
+func (e *redactedJoinError) Unwrap() []error {
+	return e.errs
+}
+
+// WrapError returns error whose text is filtered with the regular expressions. The original error is returned by
+// Unwrap, so errors.Is and errors.As can still find the errors in its chain. WrapError returns nil for nil error.
+func WrapError(filter PersonalDataFilter, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &redactedError{filter: filter, err: err}
+}
+
+// WrapJoinedErrors filters every error in the tree created by errors.Join. The joined errors are replaced with
+// errors which unwrap to their filtered children and the other errors are wrapped with WrapError. The errors
+// which wrap joined errors with the single error Unwrap method are wrapped as a whole.
+func WrapJoinedErrors(filter PersonalDataFilter, err error) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return WrapError(filter, err)
+	}
+
+	errs := []error{}
+	for _, child := range joined.Unwrap() {
+		if child != nil {
+			errs = append(errs, WrapJoinedErrors(filter, child))
+		}
+	}
+
+	return &redactedJoinError{filter: filter, err: err, errs: errs}
+}
@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	errorEmail = "some@mail.com"
+	errorIP    = "192.168.0.1"
+)
+
+type userError struct {
+	email string
+}
+
+func (e *userError) Error() string {
+	return "invalid user " + e.email
+}
+
+func TestWrapError(t *testing.T) {
+	Convey("WrapError", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should return nil for nil error", func() {
+			So(WrapError(filter, nil), ShouldBeNil)
+		})
+
+		Convey("Should filter the error text", func() {
+			err := WrapError(filter, fmt.Errorf("user %s not found", errorEmail))
+			So(err.Error(), ShouldEqual, "user "+filteredString+" not found")
+			So(fmt.Sprintf("%v", err), ShouldEqual, "user "+filteredString+" not found")
+		})
+
+		Convey("Should keep the original chain", func() {
+			original := fmt.Errorf("user %s: %w", errorEmail, &userError{email: errorEmail})
+			err := WrapError(filter, fmt.Errorf("request failed: %w", original))
+
+			So(errors.Is(err, original), ShouldBeTrue)
+			So(errors.Unwrap(errors.Unwrap(err)), ShouldEqual, original)
+
+			var target *userError
+			So(errors.As(err, &target), ShouldBeTrue)
+			So(target.email, ShouldEqual, errorEmail)
+		})
+	})
+
+	Convey("WrapJoinedErrors", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should return nil for nil error", func() {
+			So(WrapJoinedErrors(filter, nil), ShouldBeNil)
+		})
+
+		Convey("Should wrap the errors which are not joined", func() {
+			err := WrapJoinedErrors(filter, &userError{email: errorEmail})
+			So(err.Error(), ShouldEqual, "invalid user "+filteredString)
+			So(errors.Unwrap(err), ShouldResemble, &userError{email: errorEmail})
+		})
+
+		Convey("Should filter every error in the tree", func() {
+			nested := errors.Join(&userError{email: errorEmail}, fmt.Errorf("ip %s: %w", errorIP, io.EOF))
+			err := WrapJoinedErrors(filter, errors.Join(nested, nil, os.ErrNotExist))
+
+			So(err.Error(), ShouldEqual, "invalid user "+filteredString+"\nip "+filteredString+": EOF\nfile does not exist")
+			So(errors.Is(err, io.EOF), ShouldBeTrue)
+			So(errors.Is(err, os.ErrNotExist), ShouldBeTrue)
+
+			var target *userError
+			So(errors.As(err, &target), ShouldBeTrue)
+
+			errs := err.(interface{ Unwrap() []error }).Unwrap()
+			So(errs, ShouldHaveLength, 2)
+			So(errs[1].Error(), ShouldEqual, "file does not exist")
+
+			children := errs[0].(interface{ Unwrap() []error }).Unwrap()
+			So(children, ShouldHaveLength, 2)
+			So(children[0].Error(), ShouldEqual, "invalid user "+filteredString)
+			So(children[1].Error(), ShouldEqual, "ip "+filteredString+": EOF")
+		})
+	})
+}
@@ -1,11 +1,19 @@
 package filter
 
 import (
+	"context"
+	"net/http"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
 )
 
+var (
+	personalDataRedactorType = reflect.TypeOf((*PersonalDataRedactor)(nil)).Elem()
+	personalDataRemoverType  = reflect.TypeOf((*PersonalDataRemover)(nil)).Elem()
+)
+
 const (
 	personalDataFilterTagName = "pdfilter"
 	noFilterFlagName          = "nofilter"
@@ -17,6 +25,13 @@ type personalDataFilter struct {
 	matchFilterFunc        *MatchFilterFunc
 	personalDataRegExp     *regexp.Regexp
 	personalDataProperties []string
+	mapKeyCollisionPolicy  *MapKeyCollisionPolicy
+	concurrency            *concurrencyConfig
+	filterURLs             bool
+	sensitiveHeaders       []string
+	sensitiveCookies       []string
+	// ctx is set only on the copies of the filter created by RemovePersonalDataContext.
+	ctx context.Context
 }
 
 func (filter *personalDataFilter) RemovePersonalData(input interface{}) interface{} {
@@ -26,15 +41,36 @@ func (filter *personalDataFilter) RemovePersonalData(input interface{}) interfac
 
 	inputType := reflect.TypeOf(input)
 
-	// We don't need to filter zero values.
+	// We don't need to filter zero values. The nil pointers are skipped before their methods are called,
+	// because the methods with pointer receivers may dereference them.
 	if reflect.DeepEqual(input, reflect.Zero(inputType).Interface()) {
 		return input
 	}
 
+	// Custom and generated methods are checked before the reflection. The methods promoted from embedded fields
+	// are not called, because they filter only the embedded values. The embedded values are filtered as fields.
+	if redactor, ok := input.(PersonalDataRedactor); ok && !isPointerToImplementation(inputType, personalDataRedactorType) &&
+		!isPromotedMethod(inputType, "RedactPersonalData") {
+		if res, ok := customResult(inputType, redactor.RedactPersonalData(filter)); ok {
+			return res
+		}
+	}
+
+	if remover, ok := input.(PersonalDataRemover); ok && !isPointerToImplementation(inputType, personalDataRemoverType) &&
+		!isPromotedMethod(inputType, "RemovePersonalData") {
+		if res, ok := customResult(inputType, remover.RemovePersonalData(filter)); ok {
+			return res
+		}
+	}
+
 	switch inputType.Kind() {
 	case reflect.String:
 		return filter.handleString(input)
 	case reflect.Slice:
+		if isBytesType(inputType) {
+			return filter.handleBytes(input)
+		}
+
 		inputValue := reflect.ValueOf(input)
 		res := reflect.MakeSlice(inputType, inputValue.Len(), inputValue.Cap())
 		return filter.handleCollection(inputValue, res)
@@ -45,8 +81,24 @@ func (filter *personalDataFilter) RemovePersonalData(input interface{}) interfac
 		inputArray := reflect.ValueOf(input)
 		return filter.handleCollection(inputArray, res)
 	case reflect.Map:
+		if inputType == headerType {
+			return filter.handleHeader(input.(http.Header))
+		}
+
+		if inputType == urlValuesType {
+			return filter.handleURLValues(input.(url.Values))
+		}
+
 		return filter.handleMap(input)
 	case reflect.Struct:
+		if inputType == bufferType {
+			return filter.handleBuffer(input)
+		}
+
+		if inputType == cookieType {
+			return filter.handleCookie(input.(http.Cookie))
+		}
+
 		return filter.handleStruct(input)
 	case reflect.Ptr:
 		return filter.handlePointer(input)
@@ -55,23 +107,55 @@ func (filter *personalDataFilter) RemovePersonalData(input interface{}) interfac
 	}
 }
 
-func (filter *personalDataFilter) handleString(input interface{}) interface{} {
-	var filtered string
+// FilterString replaces the personal data found by the regular expressions in the provided string.
+// The URLs are filtered with FilterURL when FilterURLs is used.
+func (filter *personalDataFilter) FilterString(input string) string {
+	if filter.filterURLs {
+		return filter.filterURLsInString(input)
+	}
+
+	return filter.replaceAllString(input)
+}
+
+// replaceAllString replaces the personal data found by the regular expressions in the provided string.
+func (filter *personalDataFilter) replaceAllString(input string) string {
 	if filter.matchFilterFunc != nil {
-		filtered = filter.personalDataRegExp.ReplaceAllStringFunc(input.(string), *filter.matchFilterFunc)
-	} else {
-		filtered = filter.personalDataRegExp.ReplaceAllString(input.(string), filter.mask)
+		return filter.personalDataRegExp.ReplaceAllStringFunc(input, *filter.matchFilterFunc)
+	}
+
+	return filter.personalDataRegExp.ReplaceAllString(input, filter.mask)
+}
+
+// FilterProperty replaces the whole value with the mask if the name is one of the personal data properties.
+// Otherwise the value is filtered with FilterString.
+func (filter *personalDataFilter) FilterProperty(name, value string) string {
+	if filter.IsPersonalDataProperty(name) {
+		return filter.mask
 	}
 
-	return filtered
+	return filter.FilterString(value)
+}
+
+// Mask returns the string which replaces the personal data.
+func (filter *personalDataFilter) Mask() string {
+	return filter.mask
+}
+
+func (filter *personalDataFilter) handleString(input interface{}) interface{} {
+	inputValue := reflect.ValueOf(input)
+	filtered := filter.FilterString(inputValue.String())
+
+	// The input may be of a named string type, e.g. json.Number. We need to return value of the same type.
+	return reflect.ValueOf(filtered).Convert(inputValue.Type()).Interface()
 }
 
 func (filter *personalDataFilter) handleCollection(input, res reflect.Value) interface{} {
-	for i := 0; i < input.Len(); i++ {
+	// Each item is set on its own index, so the items can be filtered concurrently.
+	filter.forEachIndex(input.Len(), func(i int) {
 		v := input.Index(i)
 		filteredValue := filter.RemovePersonalData(v.Interface())
-		res.Index(i).Set(reflect.ValueOf(filteredValue))
-	}
+		res.Index(i).Set(valueOf(filteredValue, res.Type().Elem()))
+	})
 
 	return res.Interface()
 }
@@ -82,8 +166,17 @@ func (filter *personalDataFilter) handleMap(input interface{}) interface{} {
 	mapValue := reflect.ValueOf(input)
 	res := reflect.MakeMap(mapValue.Type())
 	keys := mapValue.MapKeys()
+	filteredKeys := filter.filterMapKeys(keys)
+	// The map can't be modified concurrently. The values are filtered concurrently and set after that.
+	filteredValues := make([]reflect.Value, len(keys))
+
+	filter.forEachIndex(len(keys), func(i int) {
+		k := keys[i]
+		if !filteredKeys[i].IsValid() {
+			// The key collides with another one or it can't hold the filtered value.
+			return
+		}
 
-	for _, k := range keys {
 		v := mapValue.MapIndex(k)
 
 		// The v will be interface for map[string]interface{} and
@@ -93,11 +186,17 @@ func (filter *personalDataFilter) handleMap(input interface{}) interface{} {
 		// and map[string]string the same way.
 		valueInterface := v.Interface()
 		realValue := reflect.ValueOf(valueInterface)
-		if k.Kind() == reflect.String && filter.isFieldPersonalDataString(realValue, k.Interface().(string)) {
-			res.SetMapIndex(k, reflect.ValueOf(filter.mask))
+		if k.Kind() == reflect.String && filter.isFieldPersonalDataText(realValue, k.String()) {
+			filteredValues[i] = reflect.ValueOf(filter.maskOf(realValue))
 		} else {
 			filteredValue := filter.RemovePersonalData(valueInterface)
-			res.SetMapIndex(k, reflect.ValueOf(filteredValue))
+			filteredValues[i] = valueOf(filteredValue, mapValue.Type().Elem())
+		}
+	})
+
+	for i, filteredKey := range filteredKeys {
+		if filteredKey.IsValid() {
+			res.SetMapIndex(filteredKey, filteredValues[i])
 		}
 	}
 
@@ -127,13 +226,13 @@ func (filter *personalDataFilter) handleStruct(input interface{}) interface{} {
 		}
 
 		var filteredField interface{}
-		if filter.isFieldPersonalDataString(fieldValue, field.Name) {
-			filteredField = filter.mask
+		if filter.isFieldPersonalDataText(fieldValue, field.Name) {
+			filteredField = filter.maskOf(fieldValue)
 		} else {
 			filteredField = filter.RemovePersonalData(fieldValue.Interface())
 		}
 
-		inputValueCopy.Field(i).Set(reflect.ValueOf(filteredField))
+		inputValueCopy.Field(i).Set(valueOf(filteredField, field.Type))
 	}
 
 	return inputValueCopy.Interface()
@@ -158,8 +257,13 @@ func (filter *personalDataFilter) handlePointer(input interface{}) interface{} {
 	return slice.Index(0).Addr().Interface()
 }
 
-func (filter *personalDataFilter) isFieldPersonalDataString(value reflect.Value, fieldName string) bool {
-	return value.Kind() == reflect.String && indexOfString(filter.personalDataProperties, strings.ToLower(fieldName)) >= 0
+func (filter *personalDataFilter) isFieldPersonalDataText(value reflect.Value, fieldName string) bool {
+	return isTextValue(value) && filter.IsPersonalDataProperty(fieldName)
+}
+
+// IsPersonalDataProperty checks if the name is one of the personal data properties. The names are case insensitive.
+func (filter *personalDataFilter) IsPersonalDataProperty(name string) bool {
+	return indexOfString(filter.personalDataProperties, strings.ToLower(name)) >= 0
 }
 
 func (filter *personalDataFilter) getTagConfig(field reflect.StructField) *filterTagConfig {
@@ -26,6 +26,72 @@ type nestedStruct struct {
 	NotPersonal string
 }
 
+type generatedStruct struct {
+	Email string
+}
+
+type redactorStruct struct {
+	Tier    string
+	Address string
+	Nested  nestedStruct
+}
+
+func (v redactorStruct) RedactPersonalData(f PersonalDataFilter) interface{} {
+	return redactorStruct{Tier: v.Tier, Nested: f.RemovePersonalData(v.Nested).(nestedStruct)}
+}
+
+type pointerRedactorStruct struct {
+	Email string
+}
+
+func (v *pointerRedactorStruct) RedactPersonalData(f PersonalDataFilter) interface{} {
+	return &pointerRedactorStruct{Email: "redacted"}
+}
+
+type EmbeddedRedactor struct {
+	Tier string
+}
+
+func (v EmbeddedRedactor) RedactPersonalData(f PersonalDataFilter) interface{} {
+	return EmbeddedRedactor{Tier: "redacted"}
+}
+
+type embeddingStruct struct {
+	EmbeddedRedactor
+	Email   string
+	Pointer *pointerRedactorStruct
+}
+
+type TierRedactor struct {
+	Tier string
+}
+
+func (v *TierRedactor) RedactPersonalData(f PersonalDataFilter) interface{} {
+	return &TierRedactor{Tier: v.Tier + "-redacted"}
+}
+
+type pointerEmbeddingStruct struct {
+	*TierRedactor
+	Email string
+}
+
+type generatedEmbeddingStruct struct {
+	*generatedStruct
+	Account string
+}
+
+type otherTypeRedactorStruct struct {
+	Email string
+}
+
+func (v otherTypeRedactorStruct) RedactPersonalData(f PersonalDataFilter) interface{} {
+	return "redacted"
+}
+
+func (v generatedStruct) RemovePersonalData(f PersonalDataFilter) interface{} {
+	return generatedStruct{Email: "generated-" + FilterProperty(f, "Email", v.Email)}
+}
+
 func TestPersonalDataFilter(t *testing.T) {
 	notPersonalDataString := "not-personal"
 
@@ -296,6 +362,17 @@ func TestPersonalDataFilter(t *testing.T) {
 			So(result, ShouldBeNil)
 		})
 
+		Convey("Should keep the nil interfaces in structs and collections", func() {
+			type withInterface struct {
+				Email string
+				Extra interface{}
+			}
+
+			So(filter.RemovePersonalData(withInterface{Email: "some@mail.com"}), ShouldResemble, withInterface{Email: filteredString})
+			So(filter.RemovePersonalData([]interface{}{nil, "some@mail.com"}), ShouldResemble, []interface{}{nil, filteredString})
+			So(filter.RemovePersonalData(map[string]interface{}{"key": nil}), ShouldResemble, map[string]interface{}{"key": nil})
+		})
+
 		Convey("Should handle zero values correctly", func() {
 			var arr [4]string
 			var slc []string
@@ -360,6 +437,82 @@ func TestPersonalDataFilter(t *testing.T) {
 			So(result, ShouldResemble, expected)
 		})
 
+		Convey("Should use the generated methods", func() {
+			input := generatedStruct{Email: email}
+			expected := generatedStruct{Email: "generated-" + filteredString}
+
+			result := filter.RemovePersonalData(input)
+			So(result, ShouldResemble, expected)
+
+			result = filter.RemovePersonalData(&input)
+			So(result, ShouldResemble, &expected)
+
+			result = filter.RemovePersonalData([]generatedStruct{input})
+			So(result, ShouldResemble, []generatedStruct{expected})
+		})
+
+		Convey("Should let the types redact themselves", func() {
+			input := redactorStruct{Tier: email, Address: notPersonalDataString, Nested: nStruct}
+			expected := redactorStruct{Tier: email, Nested: filteredNStruct}
+
+			result := filter.RemovePersonalData(input)
+			So(result, ShouldResemble, expected)
+
+			result = filter.RemovePersonalData(&input)
+			So(result, ShouldResemble, &expected)
+
+			result = filter.RemovePersonalData(map[string]interface{}{"customer": input})
+			So(result, ShouldResemble, map[string]interface{}{"customer": expected})
+
+			result = filter.RemovePersonalData(&pointerRedactorStruct{Email: email})
+			So(result, ShouldResemble, &pointerRedactorStruct{Email: "redacted"})
+		})
+
+		Convey("Should not call the redactors of nil pointers", func() {
+			So(func() { filter.RemovePersonalData((*pointerRedactorStruct)(nil)) }, ShouldNotPanic)
+
+			input := embeddingStruct{Email: email}
+			So(filter.RemovePersonalData(input), ShouldResemble, embeddingStruct{Email: filteredString})
+		})
+
+		Convey("Should not call the methods promoted from embedded nil pointers", func() {
+			So(filter.RemovePersonalData(pointerEmbeddingStruct{Email: email}), ShouldResemble, pointerEmbeddingStruct{Email: filteredString})
+			So(filter.RemovePersonalData(generatedEmbeddingStruct{Account: "id"}), ShouldResemble, generatedEmbeddingStruct{Account: filteredString})
+
+			input := pointerEmbeddingStruct{TierRedactor: &TierRedactor{Tier: "gold"}, Email: email}
+			expected := pointerEmbeddingStruct{TierRedactor: &TierRedactor{Tier: "gold-redacted"}, Email: filteredString}
+			So(filter.RemovePersonalData(input), ShouldResemble, expected)
+			So(filter.RemovePersonalData(&input), ShouldResemble, &expected)
+		})
+
+		Convey("Should not use the redactors promoted from embedded fields", func() {
+			input := embeddingStruct{EmbeddedRedactor: EmbeddedRedactor{Tier: "gold"}, Email: email, Pointer: &pointerRedactorStruct{Email: email}}
+			expected := embeddingStruct{
+				EmbeddedRedactor: EmbeddedRedactor{Tier: "redacted"},
+				Email:            filteredString,
+				Pointer:          &pointerRedactorStruct{Email: "redacted"},
+			}
+
+			So(filter.RemovePersonalData(input), ShouldResemble, expected)
+			So(filter.RemovePersonalData(&input), ShouldResemble, &expected)
+			So(filter.RemovePersonalData(struct{ Value embeddingStruct }{input}), ShouldResemble, struct{ Value embeddingStruct }{expected})
+		})
+
+		Convey("Should filter the values with reflection when the redactors return other types", func() {
+			So(filter.RemovePersonalData(otherTypeRedactorStruct{Email: email}), ShouldResemble, otherTypeRedactorStruct{Email: filteredString})
+		})
+
+		Convey("FilterProperty", func() {
+			Convey("Should mask the personal data properties", func() {
+				So(FilterProperty(filter, "Email", notPersonalDataString), ShouldEqual, filteredString)
+			})
+
+			Convey("Should filter the other properties with the regular expressions", func() {
+				So(FilterProperty(filter, "notEmail", notPersonalDataString), ShouldEqual, notPersonalDataString)
+				So(FilterProperty(filter, "notEmail", email), ShouldEqual, filteredString)
+			})
+		})
+
 		Convey("Should respect field tag configuration", func() {
 			type tags struct {
 				Email string `pdfilter:"nofilter"`
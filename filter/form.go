@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"reflect"
+)
+
+const (
+	contentTypeHeader        = "Content-Type"
+	contentDispositionHeader = "Content-Disposition"
+	formDataDisposition      = "form-data"
+	fileSummaryContentType   = "text/plain; charset=utf-8"
+	fileSummaryTemplate      = "[file name=%q content-type=%q size=%d]"
+)
+
+var urlValuesType = reflect.TypeOf(url.Values{})
+
+// FilterForm reads application/x-www-form-urlencoded body from the reader and writes it filtered to the writer.
+// The values of the fields with personal data property names are replaced with the mask. All other names and
+// values are filtered with the regular expressions.
+func FilterForm(filter PersonalDataFilter, r io.Reader, w io.Writer) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterForm(r, w)
+}
+
+func (filter *personalDataFilter) filterForm(r io.Reader, w io.Writer) error {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// The query parameters have the same format. The order of the fields is preserved.
+	_, err = io.WriteString(w, filter.filterURLParams(string(body)))
+	return err
+}
+
+// FilterMultipart reads multipart/form-data body with the provided boundary from the reader and writes it
+// filtered to the writer. The fields are filtered the same way as in FilterForm. The contents of the files
+// are replaced with summary which contains the file name, the content type and the size.
+func FilterMultipart(filter PersonalDataFilter, r io.Reader, w io.Writer, boundary string) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterMultipart(r, w, boundary)
+}
+
+func (filter *personalDataFilter) filterMultipart(r io.Reader, w io.Writer, boundary string) error {
+	reader := multipart.NewReader(r, boundary)
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := filter.filterMultipartPart(part, writer); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (filter *personalDataFilter) filterMultipartPart(part *multipart.Part, writer *multipart.Writer) error {
+	header := make(textproto.MIMEHeader, len(part.Header))
+	for name, values := range part.Header {
+		filteredValues := make([]string, len(values))
+		for i, value := range values {
+			filteredValues[i] = filter.filterHeaderValue(name, value)
+		}
+
+		header[name] = filteredValues
+	}
+
+	name := part.FormName()
+	fileName := part.FileName()
+	if fileName == "" {
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return err
+		}
+
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(partWriter, filter.FilterProperty(name, string(value)))
+		return err
+	}
+
+	// The contents of the files are replaced with summary.
+	size, err := io.Copy(ioutil.Discard, part)
+	if err != nil {
+		return err
+	}
+
+	fileName = filter.FilterString(fileName)
+	header.Set(contentDispositionHeader, mime.FormatMediaType(formDataDisposition, map[string]string{"name": name, "filename": fileName}))
+	header.Set(contentTypeHeader, fileSummaryContentType)
+
+	partWriter, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(partWriter, fileSummaryTemplate, fileName, part.Header.Get(contentTypeHeader), size)
+	return err
+}
+
+func (filter *personalDataFilter) handleURLValues(input url.Values) interface{} {
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+
+	// The names are filtered like the map keys. The personal data properties are detected by their original names.
+	filteredNames := filter.filterKeys(names)
+	res := make(url.Values, len(input))
+	for name, values := range input {
+		filteredName, ok := filteredNames[name]
+		if !ok {
+			continue
+		}
+
+		if values == nil {
+			res[filteredName] = nil
+			continue
+		}
+
+		filteredValues := make([]string, len(values))
+		for i, value := range values {
+			filteredValues[i] = filter.FilterProperty(name, value)
+		}
+
+		res[filteredName] = filteredValues
+	}
+
+	return res
+}
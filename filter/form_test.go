@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterForms(t *testing.T) {
+	Convey("Forms", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("FilterForm", func() {
+			Convey("Should filter the fields and keep their order", func() {
+				output := new(bytes.Buffer)
+				err := FilterForm(filter, strings.NewReader("name=John&password=secret&note=from+some%40mail.com&remember"), output)
+				So(err, ShouldBeNil)
+				So(output.String(), ShouldEqual, "name=John&password=%2A%2A%2A%2A%2A&note=from+%2A%2A%2A%2A%2A&remember")
+			})
+		})
+
+		Convey("FilterMultipart", func() {
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			So(writer.WriteField("email", "not-personal"), ShouldBeNil)
+			So(writer.WriteField("note", "ip 192.168.0.1"), ShouldBeNil)
+			fileWriter, err := writer.CreateFormFile("avatar", "photo of some@mail.com")
+			So(err, ShouldBeNil)
+			_, err = fileWriter.Write([]byte("binary content"))
+			So(err, ShouldBeNil)
+			So(writer.Close(), ShouldBeNil)
+
+			Convey("Should filter the fields and replace the files with summary", func() {
+				output := new(bytes.Buffer)
+				err := FilterMultipart(filter, body, output, writer.Boundary())
+				So(err, ShouldBeNil)
+
+				reader := multipart.NewReader(output, writer.Boundary())
+				form, err := reader.ReadForm(1024)
+				So(err, ShouldBeNil)
+				So(form.Value, ShouldResemble, map[string][]string{"email": {filteredString}, "note": {"ip " + filteredString}})
+
+				file := form.File["avatar"][0]
+				So(file.Filename, ShouldEqual, "photo of "+filteredString)
+				So(file.Header.Get("Content-Type"), ShouldEqual, fileSummaryContentType)
+
+				f, err := file.Open()
+				So(err, ShouldBeNil)
+				content := new(bytes.Buffer)
+				_, err = content.ReadFrom(f)
+				So(err, ShouldBeNil)
+				So(content.String(), ShouldEqual, `[file name="photo of *****" content-type="application/octet-stream" size=14]`)
+			})
+
+			Convey("Should filter the part headers", func() {
+				body := new(bytes.Buffer)
+				writer := multipart.NewWriter(body)
+				partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+					"Content-Disposition": {`form-data; name="field"`},
+					"Authorization":       {"Bearer token"},
+				})
+				So(err, ShouldBeNil)
+				_, err = partWriter.Write([]byte("value"))
+				So(err, ShouldBeNil)
+				So(writer.Close(), ShouldBeNil)
+
+				output := new(bytes.Buffer)
+				So(FilterMultipart(filter, body, output, writer.Boundary()), ShouldBeNil)
+				So(output.String(), ShouldContainSubstring, "Authorization: *****")
+				So(output.String(), ShouldNotContainSubstring, "Bearer token")
+			})
+
+			Convey("Should return error for invalid body", func() {
+				err := FilterMultipart(filter, strings.NewReader("invalid"), new(bytes.Buffer), writer.Boundary())
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Should filter url.Values", func() {
+			values := url.Values{"email": {"not-personal"}, "note": {"some@mail.com", "text"}}
+			So(filter.RemovePersonalData(values), ShouldResemble, url.Values{"email": {filteredString}, "note": {filteredString, "text"}})
+		})
+	})
+}
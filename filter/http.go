@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	cookieHeader          = "Cookie"
+	setCookieHeader       = "Set-Cookie"
+	customHeaderPrefix    = "x-"
+	headerWordSeparator   = "-"
+	cookieSeparator       = ";"
+	cookieValueSeparator  = "="
+	cookieHeaderSeparator = "; "
+)
+
+var (
+	headerType = reflect.TypeOf(http.Header{})
+	cookieType = reflect.TypeOf(http.Cookie{})
+)
+
+func (filter *personalDataFilter) handleHeader(input http.Header) interface{} {
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+
+	// The names are filtered like the map keys. The sensitive headers are detected by their original names.
+	filteredNames := filter.filterKeys(names)
+	res := make(http.Header, len(input))
+	for name, values := range input {
+		filteredName, ok := filteredNames[name]
+		if !ok {
+			continue
+		}
+
+		if values == nil {
+			res[filteredName] = nil
+			continue
+		}
+
+		filteredValues := make([]string, len(values))
+		for i, value := range values {
+			filteredValues[i] = filter.filterHeaderValue(name, value)
+		}
+
+		res[filteredName] = filteredValues
+	}
+
+	return res
+}
+
+func (filter *personalDataFilter) filterHeaderValue(name, value string) string {
+	if filter.isSensitiveHeader(name) {
+		return filter.mask
+	}
+
+	switch http.CanonicalHeaderKey(name) {
+	case cookieHeader:
+		return filter.filterCookieHeader(value)
+	case setCookieHeader:
+		return filter.filterSetCookieHeader(value)
+	default:
+		return filter.FilterString(value)
+	}
+}
+
+// isSensitiveHeader checks if the header is one of the sensitive headers or if its name
+// is personal data property, e.g. X-User-Email is checked as useremail.
+func (filter *personalDataFilter) isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	if indexOfString(filter.sensitiveHeaders, name) >= 0 {
+		return true
+	}
+
+	property := strings.Replace(strings.TrimPrefix(name, customHeaderPrefix), headerWordSeparator, "", -1)
+	return filter.IsPersonalDataProperty(property)
+}
+
+// filterCookieHeader filters the value of Cookie header, e.g. "name1=value1; name2=value2".
+func (filter *personalDataFilter) filterCookieHeader(value string) string {
+	cookies := strings.Split(value, cookieSeparator)
+	for i, cookie := range cookies {
+		cookies[i] = filter.filterCookiePair(strings.TrimSpace(cookie))
+	}
+
+	return strings.Join(cookies, cookieHeaderSeparator)
+}
+
+// filterSetCookieHeader filters the value of Set-Cookie header, e.g. "name=value; Path=/; HttpOnly".
+func (filter *personalDataFilter) filterSetCookieHeader(value string) string {
+	parts := strings.SplitN(value, cookieSeparator, 2)
+	parts[0] = filter.filterCookiePair(strings.TrimSpace(parts[0]))
+	if len(parts) > 1 {
+		// The attributes may contain personal data too, e.g. the domain.
+		parts[1] = filter.FilterString(parts[1])
+	}
+
+	return strings.Join(parts, cookieSeparator)
+}
+
+func (filter *personalDataFilter) filterCookiePair(pair string) string {
+	parts := strings.SplitN(pair, cookieValueSeparator, 2)
+	if len(parts) == 1 {
+		return filter.FilterString(pair)
+	}
+
+	return parts[0] + cookieValueSeparator + filter.filterCookieValue(parts[0], parts[1])
+}
+
+func (filter *personalDataFilter) filterCookieValue(name, value string) string {
+	if filter.isSensitiveCookie(name) {
+		return filter.mask
+	}
+
+	return filter.FilterString(value)
+}
+
+func (filter *personalDataFilter) isSensitiveCookie(name string) bool {
+	if len(filter.sensitiveCookies) == 0 {
+		// All cookies are sensitive by default. Most of them are session identifiers.
+		return true
+	}
+
+	return indexOfString(filter.sensitiveCookies, name) >= 0 || filter.IsPersonalDataProperty(name)
+}
+
+func (filter *personalDataFilter) handleCookie(input http.Cookie) interface{} {
+	res := input
+	res.Value = filter.filterCookieValue(input.Name, input.Value)
+	res.Domain = filter.FilterString(input.Domain)
+	res.Path = filter.FilterString(input.Path)
+
+	// The raw text contains the value of the cookie.
+	res.Raw = ""
+	if input.Unparsed != nil {
+		res.Unparsed = make([]string, len(input.Unparsed))
+		for i, unparsed := range input.Unparsed {
+			res.Unparsed[i] = filter.FilterString(unparsed)
+		}
+	}
+
+	return res
+}
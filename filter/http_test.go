@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPersonalDataFilterHTTP(t *testing.T) {
+	Convey("HTTP", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		header := http.Header{
+			"Authorization":   {"Bearer token"},
+			"Cookie":          {"session=abc; theme=dark"},
+			"Set-Cookie":      {"session=abc; Path=/; Domain=192.168.0.1; HttpOnly"},
+			"X-Forwarded-For": {"10.0.0.1, 10.0.0.2"},
+			"X-User-Email":    {"not-personal"},
+			"From":            {"some@mail.com"},
+			"Content-Type":    {"application/json"},
+			"X-Empty":         nil,
+		}
+
+		Convey("Should filter http.Header", func() {
+			expected := http.Header{
+				"Authorization":   {filteredString},
+				"Cookie":          {"session=*****; theme=*****"},
+				"Set-Cookie":      {"session=*****; Path=/; Domain=*****; HttpOnly"},
+				"X-Forwarded-For": {filteredString},
+				"X-User-Email":    {filteredString},
+				"From":            {filteredString},
+				"Content-Type":    {"application/json"},
+				"X-Empty":         nil,
+			}
+
+			So(filter.RemovePersonalData(header), ShouldResemble, expected)
+			So(filter.RemovePersonalData(&header), ShouldResemble, &expected)
+			So(header.Get("Authorization"), ShouldEqual, "Bearer token")
+		})
+
+		Convey("Should use the configured headers and cookies", func() {
+			configured, err := NewBuilder().
+				SetMask(filteredString).
+				SetSensitiveHeaders("X-Tenant").
+				SetSensitiveCookies("session").
+				Build()
+			So(err, ShouldBeNil)
+
+			result := configured.RemovePersonalData(http.Header{
+				"Authorization": {"Bearer token"},
+				"X-Tenant":      {"tenant"},
+				"Cookie":        {"session=abc; theme=dark; email=x"},
+			})
+			So(result, ShouldResemble, http.Header{
+				"Authorization": {"Bearer token"},
+				"X-Tenant":      {filteredString},
+				"Cookie":        {"session=*****; theme=dark; email=*****"},
+			})
+
+			added, err := NewBuilder().SetMask(filteredString).AddSensitiveHeaders("X-Tenant").Build()
+			So(err, ShouldBeNil)
+
+			result = added.RemovePersonalData(http.Header{"Authorization": {"Bearer token"}, "X-Tenant": {"tenant"}})
+			So(result, ShouldResemble, http.Header{"Authorization": {filteredString}, "X-Tenant": {filteredString}})
+		})
+
+		Convey("Should filter http.Cookie", func() {
+			cookie := http.Cookie{Name: "session", Value: "abc", Path: "/", Raw: "session=abc", Unparsed: []string{"ip 192.168.0.1"}}
+			expected := http.Cookie{Name: "session", Value: filteredString, Path: "/", Unparsed: []string{"ip " + filteredString}}
+
+			So(filter.RemovePersonalData(cookie), ShouldResemble, expected)
+			So(filter.RemovePersonalData(&cookie), ShouldResemble, &expected)
+			So(filter.RemovePersonalData([]*http.Cookie{&cookie}), ShouldResemble, []*http.Cookie{&expected})
+		})
+
+		Convey("Should fail the build if SetSensitiveHeaders and AddSensitiveHeaders are used together.", func() {
+			_, err := NewBuilder().SetSensitiveHeaders("a").AddSensitiveHeaders("b").Build()
+			So(err, ShouldBeError, errHeadersAndAdditionalHeaders)
+
+			_, err = NewBuilder().AddSensitiveHeaders("b").SetSensitiveHeaders("a").Build()
+			So(err, ShouldBeError, errHeadersAndAdditionalHeaders)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package filter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// jsonWriter writes JSON tokens and keeps the first error.
+type jsonWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (jw *jsonWriter) writeString(s string) {
+	if jw.err != nil {
+		return
+	}
+
+	_, jw.err = jw.w.WriteString(s)
+}
+
+func (jw *jsonWriter) writeValue(value interface{}) {
+	if jw.err != nil {
+		return
+	}
+
+	var encoded []byte
+	encoded, jw.err = marshalJSON(value)
+	if jw.err == nil {
+		_, jw.err = jw.w.Write(encoded)
+	}
+}
+
+// FilterJSON reads JSON from the reader and writes it filtered to the writer. The values of the personal
+// data properties are replaced with the mask and all other strings are filtered with the regular expressions.
+// The input is processed token by token, so documents of any size can be filtered. The key order
+// and the numbers are preserved. The whitespace is not preserved. When the input is invalid or incomplete,
+// the filtered tokens read before the error are written and the error is returned.
+func FilterJSON(filter PersonalDataFilter, r io.Reader, w io.Writer) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterJSON(r, w)
+}
+
+func (filter *personalDataFilter) filterJSON(r io.Reader, w io.Writer) error {
+	decoder := json.NewDecoder(r)
+	// We need the numbers as they are. The float64 values may lose precision.
+	decoder.UseNumber()
+	jw := &jsonWriter{w: bufio.NewWriter(w)}
+
+	// The input may contain more than one JSON value. Each of them is written on new line.
+	for {
+		err := filter.filterJSONValue(decoder, jw, "")
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// The values filtered before the error are written, so the prefixes of documents can be filtered.
+			jw.w.Flush()
+			return err
+		}
+
+		jw.writeString("\n")
+	}
+
+	if jw.err != nil {
+		return jw.err
+	}
+
+	return jw.w.Flush()
+}
+
+// filterJSONValue reads the next JSON value from the decoder and writes it filtered.
+// The property is the name of the object key which holds the value. It is empty for the other values.
+// Only the current path in the document is kept in memory, so the memory usage depends on the depth of the document.
+func (filter *personalDataFilter) filterJSONValue(decoder *json.Decoder, jw *jsonWriter, property string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			err = filter.filterJSONObject(decoder, jw)
+		case '[':
+			err = filter.filterJSONArray(decoder, jw)
+		default:
+			err = fmt.Errorf("unexpected JSON delimiter %s", t)
+		}
+
+		if err != nil {
+			return err
+		}
+	case string:
+		if property != "" && filter.IsPersonalDataProperty(property) {
+			jw.writeValue(filter.mask)
+		} else {
+			jw.writeValue(filter.FilterString(t))
+		}
+	case json.Number:
+		jw.writeString(t.String())
+	default:
+		// bool and nil
+		jw.writeValue(t)
+	}
+
+	return jw.err
+}
+
+func (filter *personalDataFilter) filterJSONObject(decoder *json.Decoder, jw *jsonWriter) error {
+	// Only the keys of the object are kept in memory in order to resolve the collisions of the filtered keys.
+	var used map[string]bool
+	if filter.mapKeyCollisionPolicy != nil {
+		used = map[string]bool{}
+	}
+
+	written := 0
+
+	jw.writeString("{")
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected JSON object key %v", token)
+		}
+
+		filteredKey, ok := filter.filterJSONKey(key, used)
+		if !ok {
+			// The key collides with another one. Its value is read without writing it.
+			if err := filter.filterJSONValue(decoder, &jsonWriter{w: bufio.NewWriter(ioutil.Discard)}, key); err != nil {
+				return unexpectedEOF(err)
+			}
+
+			continue
+		}
+
+		if written > 0 {
+			jw.writeString(",")
+		}
+
+		written++
+		jw.writeValue(filteredKey)
+		jw.writeString(":")
+		if err := filter.filterJSONValue(decoder, jw, key); err != nil {
+			return unexpectedEOF(err)
+		}
+	}
+
+	// Read the closing delimiter.
+	if _, err := decoder.Token(); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	jw.writeString("}")
+	return jw.err
+}
+
+// filterJSONKey filters the object key when FilterMapKeys is used. The objects are not kept in memory,
+// so the collision policy is applied in the order of the keys in the document instead of the sorted order
+// used for the maps. The used keys are updated with the returned key. False is returned when the key should be dropped.
+func (filter *personalDataFilter) filterJSONKey(key string, used map[string]bool) (string, bool) {
+	if filter.mapKeyCollisionPolicy == nil {
+		return key, true
+	}
+
+	filtered := filteredMapKey{text: key, filtered: filter.FilterString(key)}
+	for attempt := 1; ; attempt++ {
+		candidate, ok := filter.mapKeyCandidate(filtered, attempt)
+		if !ok {
+			return "", false
+		}
+
+		if !used[candidate] {
+			used[candidate] = true
+			return candidate, true
+		}
+	}
+}
+
+func (filter *personalDataFilter) filterJSONArray(decoder *json.Decoder, jw *jsonWriter) error {
+	jw.writeString("[")
+	for i := 0; decoder.More(); i++ {
+		if i > 0 {
+			jw.writeString(",")
+		}
+
+		if err := filter.filterJSONValue(decoder, jw, ""); err != nil {
+			return unexpectedEOF(err)
+		}
+	}
+
+	// Read the closing delimiter.
+	if _, err := decoder.Token(); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	jw.writeString("]")
+	return jw.err
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF. The decoder returns io.EOF
+// even when the input ends in the middle of an object or an array.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterJSON(t *testing.T) {
+	Convey("FilterJSON", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		filterJSON := func(f PersonalDataFilter, input string) (string, error) {
+			output := new(bytes.Buffer)
+			err := FilterJSON(f, strings.NewReader(input), output)
+			return output.String(), err
+		}
+
+		Convey("Should filter the personal data properties and the strings", func() {
+			input := `{
+				"zeta": "some@mail.com",
+				"email": "not-personal",
+				"alpha": {"password": "not-personal", "user": {"id": 1}, "notPersonal": "<not-personal>"},
+				"items": ["192.168.0.1", 12345678901234567890.123456789, true, null, {"pwd": 1}],
+				"empty": {},
+				"emptyArray": []
+			}`
+
+			result, err := filterJSON(filter, input)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, `{"zeta":"*****","email":"*****","alpha":{"password":"*****","user":{"id":1},"notPersonal":"<not-personal>"},"items":["*****",12345678901234567890.123456789,true,null,{"pwd":1}],"empty":{},"emptyArray":[]}`+"\n")
+		})
+
+		Convey("Should filter all values in the input", func() {
+			result, err := filterJSON(filter, `"some@mail.com" 1 {"email":"a"}`)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "\"*****\"\n1\n{\"email\":\"*****\"}\n")
+		})
+
+		Convey("Should filter the keys when the map keys filtering is enabled", func() {
+			keysFilter, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result, err := filterJSON(keysFilter, `{"some@mail.com":3}`)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, `{"*****":3}`+"\n")
+		})
+
+		Convey("Should apply the collision policy to the filtered keys in document order", func() {
+			input := `{"alice@x.com":1,"name":"n","bob@x.com":{"carol@x.com":2,"dave@x.com":3},"*****":4}`
+			policies := map[MapKeyCollisionPolicy]string{
+				MapKeyCollisionSuffix: `{"*****":1,"name":"n","*****-2":{"*****":2,"*****-2":3},"*****-3":4}`,
+				MapKeyCollisionHash: fmt.Sprintf(`{"*****":1,"name":"n","*****-%s":{"*****":2,"*****-%s":3},"*****-%s":4}`,
+					getHash("bob@x.com")[:mapKeyHashLength], getHash("dave@x.com")[:mapKeyHashLength], getHash(filteredString)[:mapKeyHashLength]),
+				MapKeyCollisionKeepFirst: `{"*****":1,"name":"n"}`,
+			}
+
+			for policy, expected := range policies {
+				keysFilter, err := NewBuilder().SetMask(filteredString).FilterMapKeys(policy).Build()
+				So(err, ShouldBeNil)
+
+				result, err := filterJSON(keysFilter, input)
+				So(err, ShouldBeNil)
+				So(result, ShouldEqual, expected+"\n")
+			}
+		})
+
+		Convey("Should keep the duplicate keys when the map keys filtering is disabled", func() {
+			result, err := filterJSON(filter, `{"a":1,"a":2}`)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, `{"a":1,"a":2}`+"\n")
+		})
+
+		Convey("Should return error for invalid JSON", func() {
+			_, err := filterJSON(filter, `{"email":`)
+			So(err, ShouldNotBeNil)
+
+			_, err = filterJSON(filter, `{"email":1`)
+			So(err, ShouldNotBeNil)
+
+			_, err = filterJSON(filter, `[1,2`)
+			So(err, ShouldNotBeNil)
+
+			_, err = filterJSON(filter, `{"email" 1}`)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should write the values filtered before the error", func() {
+			result, err := filterJSON(filter, `{"password":"hunter2","note":"some@mail.com","username":"jd`)
+			So(err, ShouldEqual, io.ErrUnexpectedEOF)
+			So(result, ShouldEqual, `{"password":"*****","note":"*****","username":`)
+		})
+	})
+}
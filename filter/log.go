@@ -0,0 +1,30 @@
+package filter
+
+import "io"
+
+type logWriter struct {
+	filter PersonalDataFilter
+	w      io.Writer
+}
+
+// Write filters the message. log.Logger writes each message with a single call, so the personal data can't be split
+// between two calls.
+func (lw *logWriter) Write(p []byte) (int, error) {
+	var filtered []byte
+	if built, err := builtFilter(lw.filter); err == nil {
+		filtered = built.filterBytes(p)
+	} else {
+		filtered = []byte(FilterString(lw.filter, string(p)))
+	}
+
+	if _, err := lw.w.Write(filtered); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// LogWriter returns writer which filters the output of log.Logger and writes it to the provided writer.
+func LogWriter(filter PersonalDataFilter, w io.Writer) io.Writer {
+	return &logWriter{filter: filter, w: w}
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestLogWriter(t *testing.T) {
+	Convey("LogWriter", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should filter the output of log.Logger", func() {
+			output := new(bytes.Buffer)
+			logger := log.New(LogWriter(filter, output), "prefix ", 0)
+			logger.Printf("user %s from %s logged in", "some@mail.com", "192.168.0.1")
+			logger.Print("second message")
+			So(output.String(), ShouldEqual, "prefix user ***** from ***** logged in\nprefix second message\n")
+		})
+
+		Convey("Should return the error of the underlying writer", func() {
+			n, err := LogWriter(filter, failingWriter{}).Write([]byte("message"))
+			So(n, ShouldEqual, 0)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
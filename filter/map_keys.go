@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const mapKeyHashLength = 8
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type filteredMapKey struct {
+	index    int
+	text     string
+	filtered string
+}
+
+// FilterKeys filters the keys with the collision policy configured with FilterMapKeys, exactly like the map keys
+// are filtered. The result contains the new key of each provided key. The keys which should be dropped are missing.
+// The keys are not changed when the map keys filtering is disabled or the filter is not created by the builder.
+func FilterKeys(filter PersonalDataFilter, keys []string) map[string]string {
+	built, err := builtFilter(filter)
+	if err != nil {
+		// The filter without configuration doesn't change the keys.
+		built = &personalDataFilter{}
+	}
+
+	return built.filterKeys(keys)
+}
+
+// filterKeys returns the filtered names of the maps with string keys which are not filtered with handleMap.
+func (filter *personalDataFilter) filterKeys(keys []string) map[string]string {
+	values := make([]reflect.Value, len(keys))
+	for i, k := range keys {
+		values[i] = reflect.ValueOf(k)
+	}
+
+	values = filter.filterMapKeys(values)
+	res := make(map[string]string, len(keys))
+	for i, k := range keys {
+		if values[i].IsValid() {
+			res[k] = values[i].String()
+		}
+	}
+
+	return res
+}
+
+// filterMapKeys returns the filtered keys in the same order as the provided ones.
+// The invalid values in the result mark the keys which should be removed from the map.
+func (filter *personalDataFilter) filterMapKeys(keys []reflect.Value) []reflect.Value {
+	if filter.mapKeyCollisionPolicy == nil {
+		return keys
+	}
+
+	res := make([]reflect.Value, len(keys))
+	used := map[interface{}]bool{}
+	changed := []filteredMapKey{}
+
+	// The keys which are not changed by the filter are reserved first.
+	// This way only the keys which contain personal data can be renamed or dropped.
+	for i, k := range keys {
+		text, ok := mapKeyText(concreteMapKey(k))
+		if ok {
+			filtered := filter.FilterString(text)
+			if filtered != text {
+				changed = append(changed, filteredMapKey{index: i, text: text, filtered: filtered})
+				continue
+			}
+		}
+
+		res[i] = k
+		used[k.Interface()] = true
+	}
+
+	// The map iteration order is random. We need to sort the keys in order to resolve the collisions deterministically.
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].text < changed[j].text
+	})
+
+	for _, c := range changed {
+		keyType := concreteMapKey(keys[c.index]).Type()
+		for attempt := 1; ; attempt++ {
+			candidate, ok := filter.mapKeyCandidate(c, attempt)
+			if !ok {
+				break
+			}
+
+			key, ok := newMapKey(keyType, candidate)
+			if !ok {
+				break
+			}
+
+			if !used[key.Interface()] {
+				res[c.index] = key
+				used[key.Interface()] = true
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// mapKeyCandidate returns the key which should be used on the provided attempt to place the filtered key in the map.
+func (filter *personalDataFilter) mapKeyCandidate(key filteredMapKey, attempt int) (string, bool) {
+	if attempt == 1 {
+		return key.filtered, true
+	}
+
+	switch *filter.mapKeyCollisionPolicy {
+	case MapKeyCollisionSuffix:
+		return fmt.Sprintf("%s-%d", key.filtered, attempt), true
+	case MapKeyCollisionHash:
+		hashed := fmt.Sprintf("%s-%x", key.filtered, sha256.Sum256([]byte(key.text)))
+		hashed = hashed[:len(key.filtered)+1+mapKeyHashLength]
+		if attempt == 2 {
+			return hashed, true
+		}
+
+		// Different keys may have the same text representation.
+		return fmt.Sprintf("%s-%d", hashed, attempt-1), true
+	default:
+		return "", false
+	}
+}
+
+// concreteMapKey returns the value of the key of map with interface keys, so its text can be filtered
+// and the filtered key can be created with its type.
+func concreteMapKey(key reflect.Value) reflect.Value {
+	if key.Kind() == reflect.Interface && !key.IsNil() {
+		return key.Elem()
+	}
+
+	return key
+}
+
+func mapKeyText(key reflect.Value) (string, bool) {
+	if key.Kind() == reflect.String {
+		return key.String(), true
+	}
+
+	if !key.Type().Implements(textMarshalerType) {
+		return "", false
+	}
+
+	text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return "", false
+	}
+
+	return string(text), true
+}
+
+func newMapKey(keyType reflect.Type, text string) (reflect.Value, bool) {
+	if keyType.Kind() == reflect.String {
+		return reflect.ValueOf(text).Convert(keyType), true
+	}
+
+	key := reflect.New(keyType)
+	if !key.Type().Implements(textUnmarshalerType) {
+		return reflect.Value{}, false
+	}
+
+	if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		return reflect.Value{}, false
+	}
+
+	return key.Elem(), true
+}
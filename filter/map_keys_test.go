@@ -0,0 +1,147 @@
+package filter
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type textKey struct {
+	value string
+}
+
+func (k textKey) MarshalText() ([]byte, error) {
+	return []byte(k.value), nil
+}
+
+func (k *textKey) UnmarshalText(text []byte) error {
+	if strings.HasPrefix(string(text), "invalid") {
+		return errors.New("invalid key")
+	}
+
+	k.value = string(text)
+	return nil
+}
+
+func TestFilterMapKeys(t *testing.T) {
+	Convey("FilterMapKeys", t, func() {
+		input := map[string]int{"alice@x.com": 1, "bob@x.com": 2, "not-personal": 3}
+
+		Convey("Should not filter the keys by default", func() {
+			f, err := NewBuilder().SetMask(filteredString).Build()
+			So(err, ShouldBeNil)
+
+			So(f.RemovePersonalData(input), ShouldResemble, input)
+		})
+
+		Convey("Should keep the first of the colliding keys", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionKeepFirst).Build()
+			So(err, ShouldBeNil)
+
+			So(f.RemovePersonalData(input), ShouldResemble, map[string]int{filteredString: 1, "not-personal": 3})
+		})
+
+		Convey("Should add suffixes to the colliding keys", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(input)
+			So(result, ShouldResemble, map[string]int{filteredString: 1, filteredString + "-2": 2, "not-personal": 3})
+		})
+
+		Convey("Should add hashes to the colliding keys", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionHash).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(input)
+			hashedKey := filteredString + "-" + getHash("bob@x.com")[:mapKeyHashLength]
+			So(result, ShouldResemble, map[string]int{filteredString: 1, hashedKey: 2, "not-personal": 3})
+		})
+
+		Convey("Should not rename the keys without personal data", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(map[string]int{"0@x.com": 1, filteredString: 2})
+			So(result, ShouldResemble, map[string]int{filteredString + "-2": 1, filteredString: 2})
+		})
+
+		Convey("Should filter the values of the personal data properties", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(map[string]string{"email": "not-personal", "alice@x.com": "alice@x.com"})
+			So(result, ShouldResemble, map[string]string{"email": filteredString, filteredString: filteredString})
+		})
+
+		Convey("Should filter encoding.TextMarshaler keys", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(map[textKey]int{{"alice@x.com"}: 1, {"not-personal"}: 2})
+			So(result, ShouldResemble, map[textKey]int{{filteredString}: 1, {"not-personal"}: 2})
+		})
+
+		Convey("Should filter the keys of the maps with interface keys", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			input := map[interface{}]int{"alice@x.com": 1, textKey{"bob@x.com"}: 2, 3: 3, "not-personal": 4}
+			expected := map[interface{}]int{filteredString: 1, textKey{filteredString}: 2, 3: 3, "not-personal": 4}
+			So(f.RemovePersonalData(input), ShouldResemble, expected)
+		})
+
+		Convey("Should filter the names of the headers and the form values", func() {
+			f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			header := http.Header{"Alice@x.com": {"value"}, "Authorization": {"secret"}, "Accept": {"text/plain"}}
+			So(f.RemovePersonalData(header), ShouldResemble, http.Header{filteredString: {"value"}, "Authorization": {filteredString}, "Accept": {"text/plain"}})
+
+			values := url.Values{"alice@x.com": {"1"}, "bob@x.com": nil, "password": {"secret"}}
+			So(f.RemovePersonalData(values), ShouldResemble, url.Values{filteredString: {"1"}, filteredString + "-2": nil, "password": {filteredString}})
+		})
+
+		Convey("Should remove the keys which can't hold the filtered value", func() {
+			f, err := NewBuilder().SetMask("invalid").FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			result := f.RemovePersonalData(map[textKey]int{{"alice@x.com"}: 1, {"not-personal"}: 2})
+			So(result, ShouldResemble, map[textKey]int{{"not-personal"}: 2})
+		})
+
+		Convey("Should not enable the key filtering if there is builder error.", func() {
+			b := NewBuilder()
+			b.err = errPDPropsAndAdditionalPDProps
+			b = b.FilterMapKeys(MapKeyCollisionSuffix)
+			So(b.mapKeyCollisionPolicy, ShouldBeNil)
+		})
+
+		Convey("FilterKeys", func() {
+			keys := []string{"alice@x.com", "bob@x.com", "not-personal"}
+
+			Convey("Should apply the collision policy", func() {
+				f, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+				So(err, ShouldBeNil)
+				So(FilterKeys(f, keys), ShouldResemble, map[string]string{"alice@x.com": filteredString, "bob@x.com": filteredString + "-2", "not-personal": "not-personal"})
+
+				f, err = NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionKeepFirst).Build()
+				So(err, ShouldBeNil)
+				So(FilterKeys(f, keys), ShouldResemble, map[string]string{"alice@x.com": filteredString, "not-personal": "not-personal"})
+			})
+
+			Convey("Should not change the keys when the map keys filtering is disabled", func() {
+				f, err := NewBuilder().SetMask(filteredString).Build()
+				So(err, ShouldBeNil)
+
+				expected := map[string]string{"alice@x.com": "alice@x.com", "bob@x.com": "bob@x.com", "not-personal": "not-personal"}
+				So(FilterKeys(f, keys), ShouldResemble, expected)
+				So(FilterKeys(upperFilter{}, keys), ShouldResemble, expected)
+			})
+		})
+	})
+}
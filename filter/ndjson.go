@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// FilterNDJSON reads newline-delimited JSON from the reader and writes it filtered to the writer line by line.
+// The lines which are not valid JSON are handled according to the options and don't stop the filtering.
+// Only the errors from the reader and the writer are returned.
+func FilterNDJSON(filter PersonalDataFilter, r io.Reader, w io.Writer, options NDJSONOptions) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterNDJSON(r, w, options)
+}
+
+func (filter *personalDataFilter) filterNDJSON(r io.Reader, w io.Writer, options NDJSONOptions) error {
+	reader := bufio.NewReader(r)
+	writer := bufio.NewWriter(w)
+	filtered := new(bytes.Buffer)
+
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if len(line) == 0 && readErr == io.EOF {
+			break
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+		filtered.Reset()
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			filtered.WriteString("\n")
+		} else if err := filter.filterNDJSONLine(line, filtered); err != nil {
+			if options.OnError != nil {
+				options.OnError(lineNumber, err)
+			}
+
+			filtered.Reset()
+			filter.handleMalformedLine(line, filtered, options)
+		}
+
+		if _, err := writer.Write(filtered.Bytes()); err != nil {
+			return err
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return writer.Flush()
+}
+
+// filterNDJSONLine writes the filtered line followed by new line to the buffer.
+func (filter *personalDataFilter) filterNDJSONLine(line []byte, filtered *bytes.Buffer) error {
+	// The line must contain exactly one JSON value. filterJSON accepts more than one.
+	if !json.Valid(line) {
+		var value interface{}
+		return json.Unmarshal(line, &value)
+	}
+
+	return filter.filterJSON(bytes.NewReader(line), filtered)
+}
+
+func (filter *personalDataFilter) handleMalformedLine(line []byte, filtered *bytes.Buffer, options NDJSONOptions) {
+	switch options.MalformedLinePolicy {
+	case MalformedLineDrop:
+		return
+	case MalformedLinePassThrough:
+		filtered.WriteString(options.Marker)
+		filtered.Write(line)
+	default:
+		filtered.Write(filter.filterBytes(line))
+	}
+
+	filtered.WriteString("\n")
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterNDJSON(t *testing.T) {
+	Convey("FilterNDJSON", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		input := strings.Join([]string{
+			`{"level":"info","email":"not-personal","msg":"user some@mail.com logged in"}`,
+			`{"level":"error","msg":"broken some@mail.com`,
+			``,
+			`[1, "192.168.0.1"]`,
+			`1 2`,
+		}, "\r\n")
+
+		type lineError struct {
+			line int
+			err  error
+		}
+
+		filterNDJSON := func(options NDJSONOptions) (string, []lineError) {
+			errs := []lineError{}
+			options.OnError = func(line int, err error) {
+				errs = append(errs, lineError{line: line, err: err})
+			}
+
+			output := new(bytes.Buffer)
+			err := FilterNDJSON(filter, strings.NewReader(input), output, options)
+			So(err, ShouldBeNil)
+			return output.String(), errs
+		}
+
+		Convey("Should scrub the malformed lines with the regular expressions", func() {
+			result, errs := filterNDJSON(NDJSONOptions{MalformedLinePolicy: MalformedLineScrub})
+			So(result, ShouldEqual, strings.Join([]string{
+				`{"level":"info","email":"*****","msg":"user ***** logged in"}`,
+				`{"level":"error","msg":"broken *****`,
+				``,
+				`[1,"*****"]`,
+				`1 2`,
+				``,
+			}, "\n"))
+
+			So(errs, ShouldHaveLength, 2)
+			So(errs[0].line, ShouldEqual, 2)
+			So(errs[0].err, ShouldNotBeNil)
+			So(errs[1].line, ShouldEqual, 5)
+		})
+
+		Convey("Should drop the malformed lines", func() {
+			result, errs := filterNDJSON(NDJSONOptions{MalformedLinePolicy: MalformedLineDrop})
+			So(result, ShouldEqual, strings.Join([]string{
+				`{"level":"info","email":"*****","msg":"user ***** logged in"}`,
+				``,
+				`[1,"*****"]`,
+				``,
+			}, "\n"))
+			So(errs, ShouldHaveLength, 2)
+		})
+
+		Convey("Should pass through the malformed lines with marker", func() {
+			result, _ := filterNDJSON(NDJSONOptions{MalformedLinePolicy: MalformedLinePassThrough, Marker: "[malformed] "})
+			So(result, ShouldEqual, strings.Join([]string{
+				`{"level":"info","email":"*****","msg":"user ***** logged in"}`,
+				`[malformed] {"level":"error","msg":"broken some@mail.com`,
+				``,
+				`[1,"*****"]`,
+				`[malformed] 1 2`,
+				``,
+			}, "\n"))
+		})
+	})
+}
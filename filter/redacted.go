@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	defaultFilter     PersonalDataFilter
+	defaultFilterOnce sync.Once
+)
+
+// RedactedValue filters the wrapped value when it is formatted or marshaled. This way the value can be passed
+// to fmt and log functions without filtering it beforehand.
+type RedactedValue struct {
+	filter PersonalDataFilter
+	value  interface{}
+}
+
+// Redacted wraps the value, so it is filtered with the default configuration when it is formatted or marshaled.
+func Redacted(value interface{}) RedactedValue {
+	defaultFilterOnce.Do(func() {
+		// The default configuration can't be invalid.
+		defaultFilter, _ = NewBuilder().Build()
+	})
+
+	return RedactedWith(defaultFilter, value)
+}
+
+// RedactedWith wraps the value, so it is filtered with the provided filter when it is formatted or marshaled.
+func RedactedWith(filter PersonalDataFilter, value interface{}) RedactedValue {
+	return RedactedValue{filter: filter, value: value}
+}
+
+// Format formats the filtered value with the provided verb and flags. The errors and the stringers are formatted
+// as text the same way fmt does. The formatted text is filtered too, because the methods of the value may return
+// personal data which can't be filtered structurally.
+func (r RedactedValue) Format(s fmt.State, verb rune) {
+	value := r.value
+	if text, ok := formatText(value, s, verb); ok {
+		value = text
+	}
+
+	formatted := fmt.Sprintf(fmt.FormatString(s, verb), r.filter.RemovePersonalData(value))
+	fmt.Fprint(s, FilterString(r.filter, formatted))
+}
+
+func (r RedactedValue) String() string {
+	return fmt.Sprintf("%v", r)
+}
+
+func (r RedactedValue) GoString() string {
+	return fmt.Sprintf("%#v", r)
+}
+
+func (r RedactedValue) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r.filter.RemovePersonalData(r.value))
+}
+
+// formatText returns the text of the error or the stringer for the verbs which use it.
+// The unexported fields which hold the text would be lost if the value is filtered structurally.
+func formatText(value interface{}, s fmt.State, verb rune) (string, bool) {
+	switch verb {
+	case 'v', 's', 'q', 'x', 'X':
+	default:
+		return "", false
+	}
+
+	// %#v uses the GoString method instead.
+	if verb == 'v' && s.Flag('#') {
+		return "", false
+	}
+
+	// fmt handles the nil pointers which panic in their methods.
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case error:
+		return v.Error(), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type redactedUser struct {
+	Name  string
+	Email string
+	Age   int
+}
+
+type redactedStringer struct {
+	email string
+}
+
+func (s redactedStringer) String() string {
+	return "user " + s.email
+}
+
+func TestRedacted(t *testing.T) {
+	Convey("Redacted", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		user := redactedUser{Name: "John", Email: "not-personal", Age: 42}
+
+		Convey("Should filter the value with the fmt verbs", func() {
+			So(fmt.Sprintf("%v", RedactedWith(filter, user)), ShouldEqual, "{John ***** 42}")
+			So(fmt.Sprintf("%+v", RedactedWith(filter, user)), ShouldEqual, "{Name:John Email:***** Age:42}")
+			So(fmt.Sprintf("%#v", RedactedWith(filter, user)), ShouldEqual, `filter.redactedUser{Name:"John", Email:"*****", Age:42}`)
+			So(fmt.Sprintf("%s", RedactedWith(filter, "from "+"some@mail.com")), ShouldEqual, "from *****")
+			So(fmt.Sprintf("%q", RedactedWith(filter, "some@mail.com")), ShouldEqual, `"*****"`)
+			So(fmt.Sprintf("%8d", RedactedWith(filter, 42)), ShouldEqual, "      42")
+		})
+
+		Convey("Should filter the output of the String and Error methods", func() {
+			So(fmt.Sprintf("%v", RedactedWith(filter, redactedStringer{email: "some@mail.com"})), ShouldEqual, "user *****")
+			So(fmt.Sprintf("%v", RedactedWith(filter, errors.New("invalid "+"some@mail.com"))), ShouldEqual, "invalid *****")
+		})
+
+		Convey("Should implement fmt.Stringer and fmt.GoStringer", func() {
+			So(RedactedWith(filter, user).String(), ShouldEqual, "{John ***** 42}")
+			So(RedactedWith(filter, user).GoString(), ShouldEqual, `filter.redactedUser{Name:"John", Email:"*****", Age:42}`)
+		})
+
+		Convey("Should implement json.Marshaler", func() {
+			res, err := json.Marshal(map[string]interface{}{"user": RedactedWith(filter, user)})
+			So(err, ShouldBeNil)
+			So(string(res), ShouldEqual, `{"user":{"Name":"John","Email":"*****","Age":42}}`)
+
+			_, err = json.Marshal(RedactedWith(filter, func() {}))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should use the default configuration", func() {
+			So(fmt.Sprintf("%v", Redacted(user)), ShouldEqual, "{John  42}")
+			So(Redacted(nil).String(), ShouldEqual, "<nil>")
+		})
+	})
+}
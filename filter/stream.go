@@ -0,0 +1,189 @@
+package filter
+
+import (
+	"errors"
+	"io"
+)
+
+const streamReadChunkSize = 4096
+
+var errWriterClosed = errors.New("write to closed filter writer")
+
+// textScrubber filters text which comes in chunks. It keeps the end of the text until more
+// data is available, so the personal data split between two chunks is still detected.
+type textScrubber struct {
+	filter       *personalDataFilter
+	maxLookahead int
+	buf          []byte
+}
+
+// textScrubber returns scrubber with the provided lookahead. The negative lookahead is treated as 0.
+func (filter *personalDataFilter) textScrubber(maxLookahead int) *textScrubber {
+	if maxLookahead < 0 {
+		maxLookahead = 0
+	}
+
+	return &textScrubber{filter: filter, maxLookahead: maxLookahead}
+}
+
+// scrub adds the chunk to the kept text and returns the filtered text which is safe to be written.
+// All kept text is filtered and returned when final is true.
+func (s *textScrubber) scrub(chunk []byte, final bool) []byte {
+	s.buf = append(s.buf, chunk...)
+
+	limit := len(s.buf)
+	if !final {
+		limit -= s.maxLookahead
+		if limit <= 0 {
+			return nil
+		}
+	}
+
+	res := []byte{}
+	consumed := 0
+	for _, match := range s.filter.personalDataRegExp.FindAllSubmatchIndex(s.buf, -1) {
+		start, end := match[0], match[1]
+		if start >= limit {
+			break
+		}
+
+		if end > limit {
+			// The match may continue in the next chunk. It is kept until more data is available, unless
+			// keeping it would exceed the memory limit. In that case the match is filtered as it is.
+			if len(s.buf)-start <= 2*s.maxLookahead {
+				limit = start
+				break
+			}
+
+			limit = end
+		}
+
+		res = append(res, s.buf[consumed:start]...)
+		res = s.filter.replaceMatch(res, s.buf, match)
+		consumed = end
+	}
+
+	res = append(res, s.buf[consumed:limit]...)
+
+	// Copy the kept text, so the underlying array doesn't grow with each chunk.
+	s.buf = append([]byte(nil), s.buf[limit:]...)
+	return res
+}
+
+// replaceMatch appends the replacement of the match to dst the same way the Replace methods of regexp.Regexp do.
+func (filter *personalDataFilter) replaceMatch(dst, src []byte, match []int) []byte {
+	if filter.matchFilterFunc != nil {
+		return append(dst, (*filter.matchFilterFunc)(string(src[match[0]:match[1]]))...)
+	}
+
+	return filter.personalDataRegExp.Expand(dst, []byte(filter.mask), src, match)
+}
+
+type filterWriter struct {
+	scrubber *textScrubber
+	w        io.Writer
+	closed   bool
+}
+
+func (fw *filterWriter) Write(p []byte) (int, error) {
+	if fw.closed {
+		return 0, errWriterClosed
+	}
+
+	if _, err := fw.w.Write(fw.scrubber.scrub(p, false)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close writes the kept text. It does not close the underlying writer.
+func (fw *filterWriter) Close() error {
+	if fw.closed {
+		return nil
+	}
+
+	fw.closed = true
+	_, err := fw.w.Write(fw.scrubber.scrub(nil, true))
+	return err
+}
+
+type filterReader struct {
+	scrubber *textScrubber
+	r        io.Reader
+	chunk    []byte
+	pending  []byte
+	err      error
+}
+
+func (fr *filterReader) Read(p []byte) (int, error) {
+	for len(fr.pending) == 0 && fr.err == nil {
+		n, err := fr.r.Read(fr.chunk)
+		fr.pending = fr.scrubber.scrub(fr.chunk[:n], err == io.EOF)
+		fr.err = err
+	}
+
+	n := copy(p, fr.pending)
+	fr.pending = fr.pending[n:]
+	if len(fr.pending) > 0 {
+		return n, nil
+	}
+
+	return n, fr.err
+}
+
+// unsupportedStream is returned by FilterWriter and FilterReader for the filters which are not created
+// by PersonalDataFilterBuilder.
+type unsupportedStream struct{}
+
+func (unsupportedStream) Write(p []byte) (int, error) {
+	return 0, ErrUnsupportedFilter
+}
+
+func (unsupportedStream) Read(p []byte) (int, error) {
+	return 0, ErrUnsupportedFilter
+}
+
+func (unsupportedStream) Close() error {
+	return nil
+}
+
+// FilterWriter returns writer which filters the text written to it with the regular expressions and writes it to w.
+// Up to 2*maxLookahead bytes are kept between the writes, so personal data up to maxLookahead bytes long is detected
+// even when it is split between two writes. The negative maxLookahead is treated as 0. Close must be called to write the kept text. It does not close w.
+// The writes fail with ErrUnsupportedFilter when the filter is not created by PersonalDataFilterBuilder.
+func FilterWriter(filter PersonalDataFilter, w io.Writer, maxLookahead int) io.WriteCloser {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return unsupportedStream{}
+	}
+
+	return built.filterWriter(w, maxLookahead)
+}
+
+// FilterReader returns reader which filters the text read from r with the regular expressions.
+// The maxLookahead has the same meaning as in FilterWriter. The reads fail with ErrUnsupportedFilter
+// when the filter is not created by PersonalDataFilterBuilder.
+func FilterReader(filter PersonalDataFilter, r io.Reader, maxLookahead int) io.Reader {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return unsupportedStream{}
+	}
+
+	return built.filterReader(r, maxLookahead)
+}
+
+func (filter *personalDataFilter) filterWriter(w io.Writer, maxLookahead int) io.WriteCloser {
+	return &filterWriter{
+		scrubber: filter.textScrubber(maxLookahead),
+		w:        w,
+	}
+}
+
+func (filter *personalDataFilter) filterReader(r io.Reader, maxLookahead int) io.Reader {
+	return &filterReader{
+		scrubber: filter.textScrubber(maxLookahead),
+		r:        r,
+		chunk:    make([]byte, streamReadChunkSize),
+	}
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterStreams(t *testing.T) {
+	Convey("Streams", t, func() {
+		email := "some@mail.com"
+		ip := "192.168.0.1"
+		input := "text " + email + " text " + ip + " text " + email
+
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		expected := FilterString(filter, input)
+
+		Convey("FilterWriter", func() {
+			Convey("Should detect personal data split between writes", func() {
+				for chunkSize := 1; chunkSize <= len(input); chunkSize++ {
+					output := new(bytes.Buffer)
+					w := FilterWriter(filter, output, 32)
+					for i := 0; i < len(input); i += chunkSize {
+						end := i + chunkSize
+						if end > len(input) {
+							end = len(input)
+						}
+
+						n, err := w.Write([]byte(input[i:end]))
+						So(err, ShouldBeNil)
+						So(n, ShouldEqual, end-i)
+					}
+
+					So(w.Close(), ShouldBeNil)
+					So(output.String(), ShouldEqual, expected)
+				}
+			})
+
+			Convey("Should keep bounded amount of text", func() {
+				output := new(bytes.Buffer)
+				w := FilterWriter(filter, output, 16)
+				_, err := w.Write([]byte(strings.Repeat("text ", 100)))
+				So(err, ShouldBeNil)
+				So(output.Len(), ShouldEqual, 500-16)
+
+				So(w.Close(), ShouldBeNil)
+				So(output.Len(), ShouldEqual, 500)
+			})
+
+			Convey("Should filter matches longer than the lookahead", func() {
+				output := new(bytes.Buffer)
+				w := FilterWriter(filter, output, 4)
+				_, err := w.Write([]byte("text " + email))
+				So(err, ShouldBeNil)
+				So(w.Close(), ShouldBeNil)
+				So(output.String(), ShouldEqual, "text "+filteredString)
+			})
+
+			Convey("Should treat the negative lookahead as 0", func() {
+				output := new(bytes.Buffer)
+				w := FilterWriter(filter, output, -1)
+				_, err := w.Write([]byte(input))
+				So(err, ShouldBeNil)
+				So(output.String(), ShouldEqual, expected)
+				So(w.Close(), ShouldBeNil)
+
+				result, err := ioutil.ReadAll(FilterReader(filter, strings.NewReader(input), -1))
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, expected)
+			})
+
+			Convey("Should use the match filter function", func() {
+				hashFilter, _ := NewBuilder().UseDefaultMatchFilterFunc().Build()
+				output := new(bytes.Buffer)
+				w := FilterWriter(hashFilter, output, 32)
+				_, err := w.Write([]byte(email[:5]))
+				So(err, ShouldBeNil)
+				_, err = w.Write([]byte(email[5:]))
+				So(err, ShouldBeNil)
+				So(w.Close(), ShouldBeNil)
+				So(output.String(), ShouldEqual, getHash(email))
+			})
+
+			Convey("Should fail to write after Close", func() {
+				w := FilterWriter(filter, new(bytes.Buffer), 32)
+				So(w.Close(), ShouldBeNil)
+				_, err := w.Write([]byte(email))
+				So(err, ShouldEqual, errWriterClosed)
+			})
+		})
+
+		Convey("FilterReader", func() {
+			Convey("Should detect personal data split between reads", func() {
+				r := FilterReader(filter, iotest.OneByteReader(strings.NewReader(input)), 32)
+				result, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, expected)
+			})
+
+			Convey("Should return the errors of the underlying reader", func() {
+				r := FilterReader(filter, iotest.TimeoutReader(strings.NewReader(input)), 32)
+				_, err := ioutil.ReadAll(r)
+				So(err, ShouldEqual, iotest.ErrTimeout)
+			})
+		})
+	})
+}
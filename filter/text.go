@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrUnsupportedFilter is returned by the functions which need the configuration of the filter
+// when the filter is not created by PersonalDataFilterBuilder.
+var ErrUnsupportedFilter = errors.New("the filter must be created by PersonalDataFilterBuilder")
+
+// builtFilter returns the filter created by PersonalDataFilterBuilder.
+func builtFilter(filter PersonalDataFilter) (*personalDataFilter, error) {
+	if res, ok := filter.(*personalDataFilter); ok {
+		return res, nil
+	}
+
+	return nil, ErrUnsupportedFilter
+}
+
+// FilterString replaces the personal data in the provided string. The filters which don't implement TextFilter
+// filter the string with RemovePersonalData.
+func FilterString(filter PersonalDataFilter, input string) string {
+	if textFilter, ok := filter.(TextFilter); ok {
+		return textFilter.FilterString(input)
+	}
+
+	if res, ok := filter.RemovePersonalData(input).(string); ok {
+		return res
+	}
+
+	return Mask(filter)
+}
+
+// FilterProperty filters the value of the property with the provided name. The filters which don't implement
+// TextFilter filter the value as item of map with the property name as key.
+func FilterProperty(filter PersonalDataFilter, name, value string) string {
+	if textFilter, ok := filter.(TextFilter); ok {
+		return textFilter.FilterProperty(name, value)
+	}
+
+	if res, ok := filter.RemovePersonalData(map[string]string{name: value}).(map[string]string); ok {
+		if filtered, ok := res[name]; ok {
+			return filtered
+		}
+	}
+
+	return FilterString(filter, value)
+}
+
+// FilterPropertyValue filters the value of the property with the provided name the same way as the values of
+// the struct fields and the maps are filtered. The text values, i.e. strings, byte slices and bytes.Buffer including
+// their named types, of the personal data properties are replaced with the mask of the same type. All other values
+// are filtered with RemovePersonalData.
+func FilterPropertyValue(filter PersonalDataFilter, name string, value interface{}) interface{} {
+	if reflectValue := reflect.ValueOf(value); isTextValue(reflectValue) && IsPersonalDataProperty(filter, name) {
+		return maskValue(Mask(filter), reflectValue)
+	}
+
+	return filter.RemovePersonalData(value)
+}
+
+// IsPersonalDataProperty checks if the name is one of the personal data properties of the filter.
+// It returns false for the filters which don't implement TextFilter.
+func IsPersonalDataProperty(filter PersonalDataFilter, name string) bool {
+	if textFilter, ok := filter.(TextFilter); ok {
+		return textFilter.IsPersonalDataProperty(name)
+	}
+
+	return false
+}
+
+// Mask returns the string which replaces the personal data. It returns empty string, the same as the default
+// configuration, for the filters which don't implement TextFilter.
+func Mask(filter PersonalDataFilter) string {
+	if textFilter, ok := filter.(TextFilter); ok {
+		return textFilter.Mask()
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// upperFilter is custom filter which implements only PersonalDataFilter.
+type upperFilter struct{}
+
+func (upperFilter) RemovePersonalData(input interface{}) interface{} {
+	switch v := input.(type) {
+	case string:
+		return strings.ToUpper(v)
+	case map[string]string:
+		res := map[string]string{}
+		for key, value := range v {
+			if key == "secret" {
+				value = "hidden"
+			}
+
+			res[key] = value
+		}
+
+		return res
+	default:
+		return input
+	}
+}
+
+func TestTextFunctions(t *testing.T) {
+	Convey("Text functions", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should use the methods of the filters created by the builder", func() {
+			So(FilterString(filter, "from some@mail.com"), ShouldEqual, "from "+filteredString)
+			So(FilterProperty(filter, "email", "text"), ShouldEqual, filteredString)
+			So(IsPersonalDataProperty(filter, "Email"), ShouldBeTrue)
+			So(Mask(filter), ShouldEqual, filteredString)
+		})
+
+		Convey("FilterPropertyValue should mask the text values of the personal data properties", func() {
+			type secret string
+			So(FilterPropertyValue(filter, "password", "text"), ShouldEqual, filteredString)
+			So(FilterPropertyValue(filter, "password", secret("text")), ShouldEqual, secret(filteredString))
+			So(FilterPropertyValue(filter, "password", []byte("text")), ShouldResemble, []byte(filteredString))
+			So(FilterPropertyValue(filter, "password", []string{"text"}), ShouldResemble, []string{"text"})
+			So(FilterPropertyValue(filter, "note", "some@mail.com"), ShouldEqual, filteredString)
+			So(FilterPropertyValue(filter, "note", nil), ShouldBeNil)
+
+			keysFilter, err := NewBuilder().SetMask(filteredString).FilterMapKeys(MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+			So(FilterPropertyValue(keysFilter, "10.0.0.1", []string{"x"}), ShouldResemble, []string{"x"})
+			So(FilterPropertyValue(upperFilter{}, "password", "text"), ShouldEqual, "TEXT")
+		})
+
+		Convey("Should use RemovePersonalData of the custom filters", func() {
+			custom := upperFilter{}
+			So(FilterString(custom, "text"), ShouldEqual, "TEXT")
+			So(FilterProperty(custom, "secret", "text"), ShouldEqual, "hidden")
+			So(FilterProperty(custom, "name", "text"), ShouldEqual, "text")
+			So(IsPersonalDataProperty(custom, "secret"), ShouldBeFalse)
+			So(Mask(custom), ShouldBeEmpty)
+			So(FilterURL(custom, "https://example.com"), ShouldEqual, "HTTPS://EXAMPLE.COM")
+			So(WrapError(custom, errors.New("not found")).Error(), ShouldEqual, "NOT FOUND")
+
+			res, err := RemovePersonalDataContext(context.Background(), custom, "text")
+			So(err, ShouldBeNil)
+			So(res, ShouldEqual, "TEXT")
+
+			output := new(bytes.Buffer)
+			_, err = LogWriter(custom, output).Write([]byte("text"))
+			So(err, ShouldBeNil)
+			So(output.String(), ShouldEqual, "TEXT")
+		})
+
+		Convey("Should not support the custom filters in the functions which need the configuration", func() {
+			custom := upperFilter{}
+			So(FilterJSON(custom, strings.NewReader("{}"), ioutil.Discard), ShouldEqual, ErrUnsupportedFilter)
+			So(FilterXML(custom, strings.NewReader("<a/>"), ioutil.Discard), ShouldEqual, ErrUnsupportedFilter)
+
+			_, err := FilterWriter(custom, ioutil.Discard, 10).Write([]byte("text"))
+			So(err, ShouldEqual, ErrUnsupportedFilter)
+
+			_, err = ioutil.ReadAll(FilterReader(custom, strings.NewReader("text"), 10))
+			So(err, ShouldEqual, ErrUnsupportedFilter)
+		})
+	})
+}
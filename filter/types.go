@@ -7,10 +7,90 @@ type PersonalDataFilter interface {
 	RemovePersonalData(input interface{}) interface{}
 }
 
+// TextFilter is implemented by the filters created by PersonalDataFilterBuilder. It filters single strings without
+// reflection. Custom implementations of PersonalDataFilter can implement it too, so the package functions and the
+// subpackages use their configuration instead of the fallbacks.
+type TextFilter interface {
+	// FilterString replaces the personal data found by the regular expressions in the provided string.
+	FilterString(input string) string
+	// FilterProperty filters the value of the property with the provided name. The whole value
+	// will be replaced with the mask if the name is one of the personal data properties.
+	FilterProperty(name, value string) string
+	// IsPersonalDataProperty checks if the name is one of the personal data properties.
+	IsPersonalDataProperty(name string) bool
+	// Mask returns the string which replaces the personal data.
+	Mask() string
+}
+
+// PersonalDataRemover is implemented by types which have reflection-free RemovePersonalData
+// methods generated by the pdfilter-gen tool. The filter will use these methods instead of reflection.
+type PersonalDataRemover interface {
+	// RemovePersonalData returns copy of the value with the personal data removed by the provided filter.
+	RemovePersonalData(filter PersonalDataFilter) interface{}
+}
+
 // MatchFilterFunc is function which will be used to replace each match found by some
 // of the registered regular expressions.
 type MatchFilterFunc func(match string) (replaced string)
 
+// PersonalDataRedactor is implemented by types which know best how to remove their own personal data.
+// The filter calls RedactPersonalData instead of reflecting over such values, so the types have full control
+// over the result. The provided filter can be used for the nested values, but calling its RemovePersonalData
+// method with the redacted value itself will cause infinite recursion. The result must have the type of the value.
+// Otherwise it is ignored and the value is filtered with reflection. This way the methods promoted from embedded
+// fields don't replace the values which embed them. The nil values are not redacted.
+type PersonalDataRedactor interface {
+	// RedactPersonalData returns the value with its personal data removed.
+	RedactPersonalData(filter PersonalDataFilter) interface{}
+}
+
+// MapKeyCollisionPolicy defines how the filter handles map keys which become equal after filtering.
+type MapKeyCollisionPolicy int
+
+const (
+	// MapKeyCollisionKeepFirst keeps only the first of the colliding keys in sorted order.
+	// The values of the other keys are dropped.
+	MapKeyCollisionKeepFirst MapKeyCollisionPolicy = iota
+	// MapKeyCollisionSuffix appends sequence number to the colliding keys, e.g. "*****", "*****-2", "*****-3".
+	MapKeyCollisionSuffix
+	// MapKeyCollisionHash appends the beginning of the sha256 sum of the original key to the colliding keys.
+	MapKeyCollisionHash
+)
+
+// MalformedLinePolicy defines how FilterNDJSON handles the lines which are not valid JSON.
+type MalformedLinePolicy int
+
+const (
+	// MalformedLineScrub filters the malformed lines as text with the regular expressions only.
+	MalformedLineScrub MalformedLinePolicy = iota
+	// MalformedLineDrop removes the malformed lines from the output.
+	MalformedLineDrop
+	// MalformedLinePassThrough writes the malformed lines as they are, prefixed with the marker.
+	// The personal data in these lines will not be filtered.
+	MalformedLinePassThrough
+)
+
+// NDJSONOptions configures FilterNDJSON.
+type NDJSONOptions struct {
+	// MalformedLinePolicy defines how the lines which are not valid JSON are handled.
+	MalformedLinePolicy MalformedLinePolicy
+	// Marker is prepended to the malformed lines when MalformedLinePassThrough is used.
+	Marker string
+	// OnError is called with the line number (starting from 1) and the error for each malformed line.
+	OnError func(line int, err error)
+}
+
+// CSVOptions configures FilterCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter. It is ',' by default. Use '\t' for TSV.
+	Comma rune
+	// NoHeader must be set when the first row contains data instead of column names.
+	NoHeader bool
+	// PersonalDataColumns contains the indexes (starting from 0) of the columns which will be masked.
+	// They are used together with the columns found in the header.
+	PersonalDataColumns []int
+}
+
 type filterTagConfig struct {
 	NoFilter bool
 }
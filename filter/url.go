@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	urlPathSeparator     = "/"
+	urlParamsSeparator   = "&"
+	urlKeyValueSeparator = "="
+)
+
+// Matches the URLs with scheme in text. The URL ends with the first whitespace or quote.
+var urlRegExp = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.\-]*://[^\s"'<>]+`)
+
+func (filter *personalDataFilter) filterURLsInString(input string) string {
+	matches := urlRegExp.FindAllStringIndex(input, -1)
+	if len(matches) == 0 {
+		return filter.replaceAllString(input)
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, match := range matches {
+		res.WriteString(filter.replaceAllString(input[last:match[0]]))
+		res.WriteString(filter.filterURL(input[match[0]:match[1]]))
+		last = match[1]
+	}
+
+	res.WriteString(filter.replaceAllString(input[last:]))
+	return res.String()
+}
+
+// FilterURL filters the URL. The user info is replaced with the mask, the values of the query and fragment
+// parameters with personal data property names are replaced with the mask and the host, the path segments
+// and the other parameters are filtered with the regular expressions. Invalid URLs are filtered as strings.
+// The filters which are not created by PersonalDataFilterBuilder filter the URL as string.
+func FilterURL(filter PersonalDataFilter, rawURL string) string {
+	if built, err := builtFilter(filter); err == nil {
+		return built.filterURL(rawURL)
+	}
+
+	return FilterString(filter, rawURL)
+}
+
+func (filter *personalDataFilter) filterURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Opaque != "" {
+		return filter.replaceAllString(rawURL)
+	}
+
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(filter.mask, filter.mask)
+		} else {
+			u.User = url.User(filter.mask)
+		}
+	}
+
+	u.Host = filter.replaceAllString(u.Host)
+
+	segments := strings.Split(u.EscapedPath(), urlPathSeparator)
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			segments[i] = filter.replaceAllString(segment)
+			continue
+		}
+
+		segments[i] = filter.filterEscaped(segment, unescaped, url.PathEscape)
+	}
+
+	u.RawPath = strings.Join(segments, urlPathSeparator)
+	u.Path, err = url.PathUnescape(u.RawPath)
+	if err != nil {
+		return filter.replaceAllString(rawURL)
+	}
+
+	u.RawQuery = filter.filterURLParams(u.RawQuery)
+
+	if fragment := u.EscapedFragment(); fragment != "" {
+		// The fragment may contain parameters, e.g. the access tokens in OAuth 2.0 implicit flow.
+		if strings.Contains(fragment, urlKeyValueSeparator) {
+			u.RawFragment = filter.filterURLParams(fragment)
+		} else {
+			u.RawFragment = filter.filterEscaped(fragment, u.Fragment, url.PathEscape)
+		}
+
+		u.Fragment, err = url.PathUnescape(u.RawFragment)
+		if err != nil {
+			return filter.replaceAllString(rawURL)
+		}
+	}
+
+	return u.String()
+}
+
+// filterURLParams filters the parameters of query or fragment. The order of the parameters is preserved.
+func (filter *personalDataFilter) filterURLParams(rawParams string) string {
+	if rawParams == "" {
+		return rawParams
+	}
+
+	params := strings.Split(rawParams, urlParamsSeparator)
+	for i, param := range params {
+		parts := strings.SplitN(param, urlKeyValueSeparator, 2)
+		key, err := url.QueryUnescape(parts[0])
+		if err != nil {
+			params[i] = filter.replaceAllString(param)
+			continue
+		}
+
+		filteredKey := filter.filterEscaped(parts[0], key, url.QueryEscape)
+		if len(parts) == 1 {
+			params[i] = filteredKey
+			continue
+		}
+
+		var value string
+		if filter.IsPersonalDataProperty(key) {
+			value = url.QueryEscape(filter.mask)
+		} else if unescaped, err := url.QueryUnescape(parts[1]); err == nil {
+			value = filter.filterEscaped(parts[1], unescaped, url.QueryEscape)
+		} else {
+			value = filter.replaceAllString(parts[1])
+		}
+
+		params[i] = filteredKey + urlKeyValueSeparator + value
+	}
+
+	return strings.Join(params, urlParamsSeparator)
+}
+
+// filterEscaped filters the unescaped text of URL part. The raw part is returned as it is when the text
+// doesn't contain personal data, so its escaping is not changed.
+func (filter *personalDataFilter) filterEscaped(raw, unescaped string, escape func(string) string) string {
+	filtered := filter.replaceAllString(unescaped)
+	if filtered == unescaped {
+		return raw
+	}
+
+	return escape(filtered)
+}
@@ -1,5 +1,13 @@
 package filter
 
+import (
+	"reflect"
+	"runtime"
+)
+
+// autogeneratedFile is the file of the wrapper functions created by the compiler for the promoted methods.
+const autogeneratedFile = "<autogenerated>"
+
 func indexOfString(collection []string, value string) int {
 	for i, v := range collection {
 		if v == value {
@@ -9,3 +17,66 @@ func indexOfString(collection []string, value string) int {
 
 	return -1
 }
+
+// isPointerToImplementation checks if the type is pointer to a type which implements the interface.
+// Such pointers implement the interface too, but we need to dereference them in order to return pointers.
+func isPointerToImplementation(t, iface reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Implements(iface)
+}
+
+// isPromotedMethod checks if the method of the type is promoted from embedded field. The promoted methods
+// receive the embedded values, which may be nil pointers, so they are not called for the embedding types.
+// The compiler creates wrapper functions for the promoted methods, so they are recognized by their file.
+func isPromotedMethod(t reflect.Type, name string) bool {
+	structType := t
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct || !hasEmbeddedField(structType) {
+		return false
+	}
+
+	method, ok := t.MethodByName(name)
+	if !ok {
+		return false
+	}
+
+	function := runtime.FuncForPC(method.Func.Pointer())
+	if function == nil {
+		return false
+	}
+
+	file, _ := function.FileLine(function.Entry())
+	return file == autogeneratedFile
+}
+
+func hasEmbeddedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueOf returns the reflect value of the filtered value. The nil interfaces are replaced with the zero value
+// of the provided type, because the invalid reflect values can't be set to the struct fields and the collections.
+func valueOf(filtered interface{}, t reflect.Type) reflect.Value {
+	if filtered == nil {
+		return reflect.Zero(t)
+	}
+
+	return reflect.ValueOf(filtered)
+}
+
+// customResult checks if the result of a custom or generated method can replace the input. The nil result
+// is replaced with the zero value of the input type.
+func customResult(inputType reflect.Type, res interface{}) (interface{}, bool) {
+	if res == nil {
+		return reflect.Zero(inputType).Interface(), true
+	}
+
+	return res, reflect.TypeOf(res).AssignableTo(inputType)
+}
@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xmlNamespaceAttr = "xmlns"
+
+var (
+	// xml.EscapeText escapes the new lines too, which changes the formatting of the document.
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\n", "&#xA;", "\r", "&#xD;", "\t", "&#x9;")
+)
+
+// xmlWriter writes raw XML tokens and keeps the first error.
+type xmlWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (xw *xmlWriter) writeString(s string) {
+	if xw.err != nil {
+		return
+	}
+
+	_, xw.err = xw.w.WriteString(s)
+}
+
+func (xw *xmlWriter) writeEscaped(escaper *strings.Replacer, s string) {
+	if xw.err != nil {
+		return
+	}
+
+	_, xw.err = escaper.WriteString(xw.w, s)
+}
+
+// FilterXML reads XML from the reader and writes it filtered to the writer. The names of the elements and
+// the attributes are used as property names. The text of the personal data elements and the values of the
+// personal data attributes are replaced with the mask. All other text and attribute values are filtered with
+// the regular expressions. The input is processed token by token and the namespace prefixes are preserved.
+func FilterXML(filter PersonalDataFilter, r io.Reader, w io.Writer) error {
+	built, err := builtFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return built.filterXML(r, w)
+}
+
+func (filter *personalDataFilter) filterXML(r io.Reader, w io.Writer) error {
+	decoder := xml.NewDecoder(r)
+	xw := &xmlWriter{w: bufio.NewWriter(w)}
+	// The names of the open elements. Only the current path in the document is kept in memory.
+	elements := []string{}
+
+	for {
+		// RawToken does not translate the namespace prefixes, so they are written as they are.
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			elements = append(elements, t.Name.Local)
+			filter.writeXMLStartElement(xw, t)
+		case xml.EndElement:
+			if len(elements) == 0 {
+				return fmt.Errorf("unexpected end element </%s>", xmlName(t.Name))
+			}
+
+			elements = elements[:len(elements)-1]
+			xw.writeString("</" + xmlName(t.Name) + ">")
+		case xml.CharData:
+			text := string(t)
+			// The whitespace between the child elements of personal data properties is kept.
+			if len(elements) > 0 && filter.IsPersonalDataProperty(elements[len(elements)-1]) && strings.TrimSpace(text) != "" {
+				xw.writeEscaped(xmlTextEscaper, filter.mask)
+			} else {
+				xw.writeEscaped(xmlTextEscaper, filter.FilterString(text))
+			}
+		case xml.Comment:
+			xw.writeString("<!--" + filter.FilterString(string(t)) + "-->")
+		case xml.ProcInst:
+			xw.writeString("<?" + t.Target + " " + string(t.Inst) + "?>")
+		case xml.Directive:
+			xw.writeString("<!" + string(t) + ">")
+		}
+
+		if xw.err != nil {
+			return xw.err
+		}
+	}
+
+	if len(elements) > 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	return xw.w.Flush()
+}
+
+func (filter *personalDataFilter) writeXMLStartElement(xw *xmlWriter, element xml.StartElement) {
+	xw.writeString("<" + xmlName(element.Name))
+	for _, attr := range element.Attr {
+		value := attr.Value
+		if attr.Name.Space != xmlNamespaceAttr && attr.Name.Local != xmlNamespaceAttr {
+			value = filter.FilterProperty(attr.Name.Local, value)
+		}
+
+		xw.writeString(" " + xmlName(attr.Name) + `="`)
+		xw.writeEscaped(xmlAttrEscaper, value)
+		xw.writeString(`"`)
+	}
+
+	xw.writeString(">")
+}
+
+// xmlName returns the name as it is written in the document. The Space of the names
+// returned by RawToken is the namespace prefix.
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
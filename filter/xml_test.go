@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterXML(t *testing.T) {
+	Convey("FilterXML", t, func() {
+		filter, err := NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		filterXML := func(input string) (string, error) {
+			output := new(bytes.Buffer)
+			err := FilterXML(filter, strings.NewReader(input), output)
+			return output.String(), err
+		}
+
+		Convey("Should filter the elements and the attributes", func() {
+			input := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE envelope>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:c="urn:customers">
+  <!-- sent by some@mail.com -->
+  <soap:Body>
+    <c:Customer id="1" email="not-personal" note="ip 192.168.0.1">
+      <c:Email>not-personal</c:Email>
+      <c:Name>John &amp; Jane</c:Name>
+      <c:Notes><![CDATA[contact some@mail.com]]></c:Notes>
+      <c:User>
+        <c:Name>John</c:Name>
+      </c:User>
+      <c:Empty/>
+    </c:Customer>
+  </soap:Body>
+</soap:Envelope>`
+			expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE envelope>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:c="urn:customers">
+  <!-- sent by ***** -->
+  <soap:Body>
+    <c:Customer id="1" email="*****" note="ip *****">
+      <c:Email>*****</c:Email>
+      <c:Name>John &amp; Jane</c:Name>
+      <c:Notes>contact *****</c:Notes>
+      <c:User>
+        <c:Name>John</c:Name>
+      </c:User>
+      <c:Empty></c:Empty>
+    </c:Customer>
+  </soap:Body>
+</soap:Envelope>`
+
+			result, err := filterXML(input)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, expected)
+		})
+
+		Convey("Should return error for invalid XML", func() {
+			_, err := filterXML(`<a><b></a>`)
+			So(err, ShouldNotBeNil)
+
+			_, err = filterXML(`<a>`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+// Package grpcfilter logs gRPC calls without personal data. The protobuf messages are filtered with protofilter
+// and all other messages are filtered with the reflection-based filter.
+package grpcfilter
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/Icenium/go-personal-data-filter/protofilter"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Record is the filtered gRPC call or message.
+// The unary calls have a single record. The streaming calls have a record for each message,
+// which holds only the message, and a final record, which holds the metadata and the status.
+type Record struct {
+	FullMethod string
+	// Metadata is the metadata sent by the client.
+	Metadata metadata.MD
+	Request  interface{}
+	Response interface{}
+	// Status is set for the finished calls.
+	Status   *status.Status
+	Duration time.Duration
+}
+
+// Sink receives the records. The context is the one of the call.
+type Sink func(ctx context.Context, record Record)
+
+func filterMessage(f filter.PersonalDataFilter, message interface{}) interface{} {
+	if m, ok := message.(proto.Message); ok {
+		return protofilter.FilterMessage(f, m)
+	}
+
+	return f.RemovePersonalData(message)
+}
+
+// filterMetadata filters the metadata the same way as the HTTP headers, because it is sent as HTTP/2 headers.
+func filterMetadata(f filter.PersonalDataFilter, md metadata.MD) metadata.MD {
+	if md == nil {
+		return nil
+	}
+
+	return metadata.MD(f.RemovePersonalData(http.Header(md)).(http.Header))
+}
+
+// filterStatus returns the filtered status of the error. The messages packed in the details are filtered too.
+func filterStatus(f filter.PersonalDataFilter, err error) *status.Status {
+	st, _ := status.FromError(err)
+	return status.FromProto(protofilter.FilterMessage(f, st.Proto()).(*spb.Status))
+}
@@ -0,0 +1,195 @@
+package grpcfilter
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns interceptor which passes the records of the unary calls to the sink.
+func UnaryServerInterceptor(f filter.PersonalDataFilter, sink Sink) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		md, _ := metadata.FromIncomingContext(ctx)
+		resp, err := handler(ctx, req)
+
+		record := Record{
+			FullMethod: info.FullMethod,
+			Metadata:   filterMetadata(f, md),
+			Request:    filterMessage(f, req),
+			Status:     filterStatus(f, err),
+			Duration:   time.Since(start),
+		}
+
+		if err == nil {
+			record.Response = filterMessage(f, resp)
+		}
+
+		sink(ctx, record)
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns interceptor which passes the records of the unary calls to the sink.
+func UnaryClientInterceptor(f filter.PersonalDataFilter, sink Sink) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		md, _ := metadata.FromOutgoingContext(ctx)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		record := Record{
+			FullMethod: method,
+			Metadata:   filterMetadata(f, md),
+			Request:    filterMessage(f, req),
+			Status:     filterStatus(f, err),
+			Duration:   time.Since(start),
+		}
+
+		if err == nil {
+			record.Response = filterMessage(f, reply)
+		}
+
+		sink(ctx, record)
+		return err
+	}
+}
+
+// StreamServerInterceptor returns interceptor which passes the records of the streamed messages
+// and of the finished streaming calls to the sink.
+func StreamServerInterceptor(f filter.PersonalDataFilter, sink Sink) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		stream := &serverStream{ServerStream: ss, filter: f, sink: sink, fullMethod: info.FullMethod}
+		err := handler(srv, stream)
+
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		sink(ss.Context(), Record{
+			FullMethod: info.FullMethod,
+			Metadata:   filterMetadata(f, md),
+			Status:     filterStatus(f, err),
+			Duration:   time.Since(start),
+		})
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns interceptor which passes the records of the streamed messages
+// and of the finished streaming calls to the sink. The call is finished when a message can't be received,
+// when the response of a call without server streaming is received or when the context is done.
+func StreamClientInterceptor(f filter.PersonalDataFilter, sink Sink) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream := &clientStream{
+			filter:        f,
+			sink:          sink,
+			ctx:           ctx,
+			fullMethod:    method,
+			serverStreams: desc.ServerStreams,
+			start:         time.Now(),
+			done:          make(chan struct{}),
+		}
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			stream.finish(err)
+			return nil, err
+		}
+
+		stream.ClientStream = cs
+		// The streams may be abandoned by canceling the context without receiving their last message.
+		go func() {
+			select {
+			case <-ctx.Done():
+				stream.finish(status.FromContextError(ctx.Err()).Err())
+			case <-stream.done:
+			}
+		}()
+
+		return stream, nil
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	filter     filter.PersonalDataFilter
+	sink       Sink
+	fullMethod string
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	s.sink(s.Context(), Record{FullMethod: s.fullMethod, Request: filterMessage(s.filter, m)})
+	return nil
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+
+	s.sink(s.Context(), Record{FullMethod: s.fullMethod, Response: filterMessage(s.filter, m)})
+	return nil
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	filter        filter.PersonalDataFilter
+	sink          Sink
+	ctx           context.Context
+	fullMethod    string
+	serverStreams bool
+	start         time.Time
+	once          sync.Once
+	done          chan struct{}
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	if err := s.ClientStream.SendMsg(m); err != nil {
+		// The error of the call is returned by RecvMsg.
+		return err
+	}
+
+	s.sink(s.ctx, Record{FullMethod: s.fullMethod, Request: filterMessage(s.filter, m)})
+	return nil
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch err {
+	case nil:
+		s.sink(s.ctx, Record{FullMethod: s.fullMethod, Response: filterMessage(s.filter, m)})
+		// The calls without server streaming have only one response, e.g. the one of CloseAndRecv.
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	case io.EOF:
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+
+	return err
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		md, _ := metadata.FromOutgoingContext(s.ctx)
+		s.sink(s.ctx, Record{
+			FullMethod: s.fullMethod,
+			Metadata:   filterMetadata(s.filter, md),
+			Status:     filterStatus(s.filter, err),
+			Duration:   time.Since(s.start),
+		})
+
+		close(s.done)
+	})
+}
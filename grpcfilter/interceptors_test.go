@@ -0,0 +1,322 @@
+package grpcfilter
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+	echoMethod     = "/pdfilter.test.Echo/Echo"
+	streamMethod   = "/pdfilter.test.Echo/Stream"
+	collectMethod  = "/pdfilter.test.Echo/Collect"
+	splitMethod    = "/pdfilter.test.Echo/Split"
+)
+
+// echoServiceDesc is written the way protoc-gen-go-grpc generates it.
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pdfilter.test.Echo",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(structpb.Struct)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return echo(ctx, in)
+			}
+
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: echoMethod}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return echo(ctx, req.(*structpb.Struct))
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName: "Stream",
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			for {
+				in := new(structpb.Struct)
+				err := stream.RecvMsg(in)
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				if err := stream.SendMsg(in); err != nil {
+					return err
+				}
+			}
+		},
+		ServerStreams: true,
+		ClientStreams: true,
+	}, {
+		// Collect merges the fields of the received messages in one response.
+		StreamName: "Collect",
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			res := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+			for {
+				in := new(structpb.Struct)
+				err := stream.RecvMsg(in)
+				if err == io.EOF {
+					return stream.SendMsg(res)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				for k, v := range in.Fields {
+					res.Fields[k] = v
+				}
+			}
+		},
+		ClientStreams: true,
+	}, {
+		// Split sends each field of the request as separate response.
+		StreamName: "Split",
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			in := new(structpb.Struct)
+			if err := stream.RecvMsg(in); err != nil {
+				return err
+			}
+
+			for k, v := range in.Fields {
+				if err := stream.SendMsg(&structpb.Struct{Fields: map[string]*structpb.Value{k: v}}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		ServerStreams: true,
+	}},
+}
+
+func echo(ctx context.Context, in *structpb.Struct) (*structpb.Struct, error) {
+	if _, ok := in.Fields["fail"]; ok {
+		details, err := structpb.NewStruct(map[string]interface{}{"email": "not-personal"})
+		if err != nil {
+			return nil, err
+		}
+
+		st, err := status.New(codes.InvalidArgument, "invalid "+email).WithDetails(details)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, st.Err()
+	}
+
+	return in, nil
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (r *recorder) sink(ctx context.Context, record Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+// wait returns the records when there are at least n of them. The server interceptors may
+// create their records after the client has received the response.
+func (r *recorder) wait(n int) []Record {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		r.mu.Lock()
+		if len(r.records) >= n {
+			records := append([]Record(nil), r.records...)
+			r.mu.Unlock()
+			return records
+		}
+
+		r.mu.Unlock()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+func newStruct(fields map[string]interface{}) *structpb.Struct {
+	res, err := structpb.NewStruct(fields)
+	So(err, ShouldBeNil)
+	return res
+}
+
+func shouldEqualProto(actual interface{}, expected ...interface{}) string {
+	if proto.Equal(actual.(proto.Message), expected[0].(proto.Message)) {
+		return ""
+	}
+
+	return "Expected: " + prototext.Format(expected[0].(proto.Message)) + "\nActual: " + prototext.Format(actual.(proto.Message))
+}
+
+func TestInterceptors(t *testing.T) {
+	Convey("Interceptors", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		serverRecords, clientRecords := &recorder{}, &recorder{}
+		listener := bufconn.Listen(1 << 20)
+		server := grpc.NewServer(
+			grpc.UnaryInterceptor(UnaryServerInterceptor(f, serverRecords.sink)),
+			grpc.StreamInterceptor(StreamServerInterceptor(f, serverRecords.sink)),
+		)
+		server.RegisterService(&echoServiceDesc, nil)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		defer server.Stop()
+
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(UnaryClientInterceptor(f, clientRecords.sink)),
+			grpc.WithStreamInterceptor(StreamClientInterceptor(f, clientRecords.sink)),
+		)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token", "x-request-ip", ip)
+
+		Convey("Should record the filtered unary calls", func() {
+			request := newStruct(map[string]interface{}{"email": "not-personal", "note": "from " + email})
+			reply := new(structpb.Struct)
+			So(conn.Invoke(ctx, echoMethod, request, reply), ShouldBeNil)
+
+			// The messages which are sent and received are not changed.
+			So(reply, shouldEqualProto, request)
+			So(request.Fields["email"].GetStringValue(), ShouldEqual, "not-personal")
+
+			filtered := newStruct(map[string]interface{}{"email": filteredString, "note": "from " + filteredString})
+			for _, records := range [][]Record{clientRecords.wait(1), serverRecords.wait(1)} {
+				So(records, ShouldHaveLength, 1)
+				So(records[0].FullMethod, ShouldEqual, echoMethod)
+				So(records[0].Metadata.Get("authorization"), ShouldResemble, []string{filteredString})
+				So(records[0].Metadata.Get("x-request-ip"), ShouldResemble, []string{filteredString})
+				So(records[0].Request, shouldEqualProto, filtered)
+				So(records[0].Response, shouldEqualProto, filtered)
+				So(records[0].Status.Code(), ShouldEqual, codes.OK)
+			}
+		})
+
+		Convey("Should filter the status and its details", func() {
+			err := conn.Invoke(ctx, echoMethod, newStruct(map[string]interface{}{"fail": true}), new(structpb.Struct))
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(status.Convert(err).Message(), ShouldEqual, "invalid "+email)
+
+			for _, records := range [][]Record{clientRecords.wait(1), serverRecords.wait(1)} {
+				So(records, ShouldHaveLength, 1)
+				So(records[0].Response, ShouldBeNil)
+				So(records[0].Status.Code(), ShouldEqual, codes.InvalidArgument)
+				So(records[0].Status.Message(), ShouldEqual, "invalid "+filteredString)
+				So(records[0].Status.Details(), ShouldHaveLength, 1)
+				So(records[0].Status.Details()[0], shouldEqualProto, newStruct(map[string]interface{}{"email": filteredString}))
+			}
+		})
+
+		Convey("Should record the filtered streamed messages", func() {
+			stream, err := conn.NewStream(ctx, &echoServiceDesc.Streams[0], streamMethod)
+			So(err, ShouldBeNil)
+
+			request := newStruct(map[string]interface{}{"ip": "not-personal"})
+			So(stream.SendMsg(request), ShouldBeNil)
+			reply := new(structpb.Struct)
+			So(stream.RecvMsg(reply), ShouldBeNil)
+			So(reply, shouldEqualProto, request)
+			So(stream.CloseSend(), ShouldBeNil)
+			So(stream.RecvMsg(new(structpb.Struct)), ShouldEqual, io.EOF)
+
+			filtered := newStruct(map[string]interface{}{"ip": filteredString})
+			for _, records := range [][]Record{clientRecords.wait(3), serverRecords.wait(3)} {
+				So(records, ShouldHaveLength, 3)
+				So(records[0].FullMethod, ShouldEqual, streamMethod)
+				So(records[0].Request, shouldEqualProto, filtered)
+				So(records[0].Metadata, ShouldBeNil)
+				So(records[1].Response, shouldEqualProto, filtered)
+				So(records[2].Request, ShouldBeNil)
+				So(records[2].Response, ShouldBeNil)
+				So(records[2].Metadata.Get("authorization"), ShouldResemble, []string{filteredString})
+				So(records[2].Status.Code(), ShouldEqual, codes.OK)
+			}
+		})
+
+		Convey("Should finish the client streaming calls when the response is received", func() {
+			stream, err := conn.NewStream(ctx, &echoServiceDesc.Streams[1], collectMethod)
+			So(err, ShouldBeNil)
+
+			So(stream.SendMsg(newStruct(map[string]interface{}{"email": "not-personal"})), ShouldBeNil)
+			So(stream.SendMsg(newStruct(map[string]interface{}{"note": ip})), ShouldBeNil)
+			So(stream.CloseSend(), ShouldBeNil)
+			So(stream.RecvMsg(new(structpb.Struct)), ShouldBeNil)
+
+			records := clientRecords.wait(4)
+			So(records, ShouldHaveLength, 4)
+			So(records[0].Request, shouldEqualProto, newStruct(map[string]interface{}{"email": filteredString}))
+			So(records[1].Request, shouldEqualProto, newStruct(map[string]interface{}{"note": filteredString}))
+			So(records[2].Response, shouldEqualProto, newStruct(map[string]interface{}{"email": filteredString, "note": filteredString}))
+			So(records[3].Metadata.Get("authorization"), ShouldResemble, []string{filteredString})
+			So(records[3].Status.Code(), ShouldEqual, codes.OK)
+		})
+
+		Convey("Should finish the server streaming calls when all responses are received", func() {
+			stream, err := conn.NewStream(ctx, &echoServiceDesc.Streams[2], splitMethod)
+			So(err, ShouldBeNil)
+
+			So(stream.SendMsg(newStruct(map[string]interface{}{"email": "not-personal", "note": ip})), ShouldBeNil)
+			So(stream.CloseSend(), ShouldBeNil)
+			So(stream.RecvMsg(new(structpb.Struct)), ShouldBeNil)
+			So(clientRecords.wait(2), ShouldHaveLength, 2)
+
+			So(stream.RecvMsg(new(structpb.Struct)), ShouldBeNil)
+			So(stream.RecvMsg(new(structpb.Struct)), ShouldEqual, io.EOF)
+
+			records := clientRecords.wait(4)
+			So(records, ShouldHaveLength, 4)
+			So(records[3].Status.Code(), ShouldEqual, codes.OK)
+		})
+
+		Convey("Should finish the streams abandoned by canceling the context", func() {
+			streamCtx, cancel := context.WithCancel(ctx)
+			stream, err := conn.NewStream(streamCtx, &echoServiceDesc.Streams[0], streamMethod)
+			So(err, ShouldBeNil)
+
+			So(stream.SendMsg(newStruct(map[string]interface{}{"ip": "not-personal"})), ShouldBeNil)
+			cancel()
+
+			records := clientRecords.wait(2)
+			So(records, ShouldHaveLength, 2)
+			So(records[1].Metadata.Get("authorization"), ShouldResemble, []string{filteredString})
+			So(records[1].Status.Code(), ShouldEqual, codes.Canceled)
+		})
+	})
+}
@@ -0,0 +1,215 @@
+// Package httpfilter logs HTTP requests and responses without personal data.
+package httpfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+// DefaultMaxBodySize is the number of bytes of the bodies which are kept when Options.MaxBodySize is not set.
+const DefaultMaxBodySize = 64 << 10
+
+const (
+	contentTypeHeader     = "Content-Type"
+	jsonContentType       = "application/json"
+	jsonContentTypeSuffix = "+json"
+	formContentType       = "application/x-www-form-urlencoded"
+	multipartContentType  = "multipart/form-data"
+	xmlContentType        = "application/xml"
+	xmlContentTypeSuffix  = "+xml"
+	textContentPrefix     = "text/"
+	binaryBodyTemplate    = "[%d bytes of %s]"
+	truncatedTemplate     = "\n[truncated, %d bytes in total]"
+	boundaryParam         = "boundary"
+)
+
+// Options configures the recording of the bodies.
+type Options struct {
+	// MaxBodySize is the maximum number of bytes of each body which are kept for the record.
+	// DefaultMaxBodySize is used when it is not positive.
+	MaxBodySize int
+}
+
+// Body is the filtered body of a request or a response.
+type Body struct {
+	ContentType string
+	// Size is the number of bytes which were transferred. It may be larger than the kept bytes.
+	Size int64
+	// Truncated is true when only the first MaxBodySize bytes were kept.
+	Truncated bool
+	// Value is the decoded and filtered body. It is json.RawMessage for JSON, url.Values for forms and string for
+	// all other bodies. The bodies which can't be decoded are filtered as text. The truncated bodies are strings.
+	// The kept bytes of the truncated JSON and form bodies are filtered structurally up to the last complete value.
+	// The truncated multipart and XML bodies and the ones which can't be filtered structurally are replaced with summary.
+	Value interface{}
+}
+
+// Record is the filtered request and response.
+type Record struct {
+	Method         string
+	URL            string
+	RemoteAddr     string
+	RequestHeader  http.Header
+	RequestBody    Body
+	Status         int
+	ResponseHeader http.Header
+	ResponseBody   Body
+	Duration       time.Duration
+}
+
+// Sink receives the records. The context is the one of the request.
+type Sink func(ctx context.Context, record Record)
+
+// Text returns the value of the body as text. The truncated bodies end with a note about their size.
+func (b Body) Text() string {
+	var text string
+	switch value := b.Value.(type) {
+	case json.RawMessage:
+		text = string(value)
+	case url.Values:
+		text = value.Encode()
+	case string:
+		text = value
+	}
+
+	if b.Truncated {
+		text += fmt.Sprintf(truncatedTemplate, b.Size)
+	}
+
+	return text
+}
+
+func (options Options) maxBodySize() int {
+	if options.MaxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+
+	return options.MaxBodySize
+}
+
+func filterHeader(f filter.PersonalDataFilter, header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	return f.RemovePersonalData(header).(http.Header)
+}
+
+// filterBody decodes the body by its content type and filters it.
+func filterBody(f filter.PersonalDataFilter, contentType string, data []byte, size int64, truncated bool) Body {
+	body := Body{ContentType: contentType, Size: size, Truncated: truncated}
+	if len(data) == 0 {
+		return body
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if truncated {
+		body.Value = filterPrefix(f, mediaType, contentType, data)
+		return body
+	}
+
+	if value, ok := decodeBody(f, mediaType, params, data); ok {
+		body.Value = value
+		return body
+	}
+
+	if !utf8.Valid(data) {
+		body.Value = fmt.Sprintf(binaryBodyTemplate, len(data), contentType)
+		return body
+	}
+
+	body.Value = filter.FilterString(f, string(data))
+	return body
+}
+
+// filterPrefix filters the kept bytes of the truncated body. The JSON and the form bodies are filtered
+// structurally up to the last complete value, so the values of the personal data properties are masked.
+// The bodies with properties which can't be filtered this way are replaced with summary.
+func filterPrefix(f filter.PersonalDataFilter, mediaType, contentType string, data []byte) string {
+	summary := fmt.Sprintf(binaryBodyTemplate, len(data), contentType)
+	filtered := new(bytes.Buffer)
+	switch {
+	case isJSON(mediaType):
+		if err := filter.FilterJSON(f, bytes.NewReader(data), filtered); err != nil && !isEndOfJSON(err, len(data)) {
+			return summary
+		}
+
+		return filtered.String()
+	case mediaType == formContentType:
+		if err := filter.FilterForm(f, bytes.NewReader(data), filtered); err != nil {
+			return summary
+		}
+
+		return filtered.String()
+	case mediaType == multipartContentType || isXML(mediaType) || !utf8.Valid(data):
+		return summary
+	default:
+		return filter.FilterString(f, string(data))
+	}
+}
+
+// isEndOfJSON checks if the error is caused by the end of the input instead of invalid JSON.
+func isEndOfJSON(err error, size int) bool {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Offset == int64(size)
+	}
+
+	return err == io.ErrUnexpectedEOF
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == jsonContentType || strings.HasSuffix(mediaType, jsonContentTypeSuffix)
+}
+
+func isXML(mediaType string) bool {
+	return mediaType == xmlContentType || strings.HasSuffix(mediaType, xmlContentTypeSuffix)
+}
+
+func decodeBody(f filter.PersonalDataFilter, mediaType string, params map[string]string, data []byte) (interface{}, bool) {
+	switch {
+	case isJSON(mediaType):
+		if !json.Valid(data) {
+			return nil, false
+		}
+
+		return f.RemovePersonalData(json.RawMessage(data)), true
+	case mediaType == formContentType:
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, false
+		}
+
+		return f.RemovePersonalData(values), true
+	case mediaType == multipartContentType:
+		filtered := new(bytes.Buffer)
+		if err := filter.FilterMultipart(f, bytes.NewReader(data), filtered, params[boundaryParam]); err != nil {
+			return nil, false
+		}
+
+		return filtered.String(), true
+	case isXML(mediaType):
+		filtered := new(bytes.Buffer)
+		if err := filter.FilterXML(f, bytes.NewReader(data), filtered); err != nil {
+			return nil, false
+		}
+
+		return filtered.String(), true
+	case strings.HasPrefix(mediaType, textContentPrefix):
+		return filter.FilterString(f, string(data)), true
+	default:
+		return nil, false
+	}
+}
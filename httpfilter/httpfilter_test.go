@@ -0,0 +1,64 @@
+package httpfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterBody(t *testing.T) {
+	Convey("filterBody", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should return empty body without value", func() {
+			So(filterBody(f, "application/json", nil, 0, false), ShouldResemble, Body{ContentType: "application/json"})
+		})
+
+		Convey("Should filter JSON structurally", func() {
+			body := filterBody(f, "application/problem+json", []byte(`{"ip":"not-personal"}`), 21, false)
+			So(body.Value, ShouldResemble, json.RawMessage(`{"ip":"*****"}`))
+		})
+
+		Convey("Should filter the invalid JSON as text", func() {
+			body := filterBody(f, "application/json", []byte(`{"note":"`+email), 22, false)
+			So(body.Value, ShouldEqual, `{"note":"*****`)
+		})
+
+		Convey("Should filter multipart bodies", func() {
+			data := new(bytes.Buffer)
+			writer := multipart.NewWriter(data)
+			So(writer.WriteField("email", "not-personal"), ShouldBeNil)
+			So(writer.Close(), ShouldBeNil)
+
+			body := filterBody(f, writer.FormDataContentType(), data.Bytes(), int64(data.Len()), false)
+			So(body.Value, ShouldContainSubstring, filteredString)
+			So(body.Value, ShouldNotContainSubstring, "not-personal")
+		})
+
+		Convey("Should filter the truncated JSON and forms structurally", func() {
+			body := filterBody(f, "application/json", []byte(`{"password":"hunter2","username":"jdoe","note":"some`), 100, true)
+			So(body.Value, ShouldEqual, `{"password":"*****","username":"*****","note":`)
+
+			body = filterBody(f, "application/x-www-form-urlencoded", []byte("password=hunter2&username=jdoe&no"), 100, true)
+			So(body.Value, ShouldEqual, "password=%2A%2A%2A%2A%2A&username=%2A%2A%2A%2A%2A&no")
+		})
+
+		Convey("Should summarize the truncated bodies which can't be filtered structurally", func() {
+			body := filterBody(f, "application/json", []byte(`{"password" "hunter2"`), 100, true)
+			So(body.Value, ShouldEqual, "[21 bytes of application/json]")
+
+			body = filterBody(f, "application/xml", []byte(`<user><password>hunter2</password><na`), 100, true)
+			So(body.Value, ShouldEqual, "[37 bytes of application/xml]")
+		})
+
+		Convey("Should summarize the binary bodies", func() {
+			body := filterBody(f, "image/png", []byte{0x89, 0x50, 0xff, 0xfe}, 4, false)
+			So(body.Value, ShouldEqual, "[4 bytes of image/png]")
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package httpfilter
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+// capture keeps the first bytes of a body and counts all of them. The transports may still write
+// the request body while the response is read, so the capture is safe for concurrent use.
+type capture struct {
+	mu        sync.Mutex
+	max       int
+	data      []byte
+	size      int64
+	truncated bool
+}
+
+func (c *capture) write(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.size += int64(len(p))
+	if free := c.max - len(c.data); free < len(p) {
+		c.truncated = true
+		p = p[:free]
+	}
+
+	c.data = append(c.data, p...)
+}
+
+func (c *capture) body(f filter.PersonalDataFilter, contentType string) Body {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return filterBody(f, contentType, c.data, c.size, c.truncated)
+}
+
+// captureReader keeps the bytes which the handler reads from the request body.
+type captureReader struct {
+	io.ReadCloser
+	capture *capture
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.capture.write(p[:n])
+	return n, err
+}
+
+// captureResponseWriter keeps the status and the first bytes of the response body.
+type captureResponseWriter struct {
+	http.ResponseWriter
+	capture *capture
+	status  int
+}
+
+func (w *captureResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.capture.write(p[:n])
+	return n, err
+}
+
+// Flush supports streaming responses when the wrapped writer supports them.
+func (w *captureResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack supports the protocol upgrades when the wrapped writer supports them.
+func (w *captureResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap is used by http.ResponseController to access the wrapped writer.
+func (w *captureResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Middleware returns middleware which passes the records of the handled requests to the sink. The bodies are
+// streamed to and from the handler as they are. Only the first bytes of the request body which the handler reads
+// and of the response body which the handler writes are kept for the record.
+func Middleware(f filter.PersonalDataFilter, sink Sink, options Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestBody := &capture{max: options.maxBodySize()}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = &captureReader{ReadCloser: r.Body, capture: requestBody}
+			}
+
+			// The handler may change the request, so the record is created from the values before it is called.
+			record := Record{
+				Method:        r.Method,
+				URL:           filter.FilterURL(f, r.URL.String()),
+				RemoteAddr:    filter.FilterString(f, r.RemoteAddr),
+				RequestHeader: filterHeader(f, r.Header),
+			}
+
+			requestContentType := r.Header.Get(contentTypeHeader)
+			responseWriter := &captureResponseWriter{ResponseWriter: w, capture: &capture{max: options.maxBodySize()}}
+			next.ServeHTTP(responseWriter, r)
+
+			record.Duration = time.Since(start)
+			record.RequestBody = requestBody.body(f, requestContentType)
+			record.Status = responseWriter.status
+			if record.Status == 0 {
+				record.Status = http.StatusOK
+			}
+
+			record.ResponseHeader = filterHeader(f, w.Header())
+			record.ResponseBody = responseWriter.capture.body(f, w.Header().Get(contentTypeHeader))
+			sink(r.Context(), record)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package httpfilter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+func TestMiddleware(t *testing.T) {
+	Convey("Middleware", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		records := []Record{}
+		sink := func(ctx context.Context, record Record) {
+			records = append(records, record)
+		}
+
+		echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			So(err, ShouldBeNil)
+
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.WriteHeader(http.StatusCreated)
+			_, err = w.Write(body)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Should record the filtered request and response", func() {
+			handler := Middleware(f, sink, Options{})(echo)
+			request := httptest.NewRequest(http.MethodPost, "/users/"+ip+"?email=not-personal&page=2", strings.NewReader(`{"email":"not-personal","note":"from `+email+`","age":42}`))
+			request.RemoteAddr = ip + ":1234"
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer token")
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			So(response.Code, ShouldEqual, http.StatusCreated)
+			So(response.Body.String(), ShouldEqual, `{"email":"not-personal","note":"from `+email+`","age":42}`)
+
+			So(records, ShouldHaveLength, 1)
+			record := records[0]
+			So(record.Method, ShouldEqual, http.MethodPost)
+			So(record.URL, ShouldEqual, "/users/%2A%2A%2A%2A%2A?email=%2A%2A%2A%2A%2A&page=2")
+			So(record.RemoteAddr, ShouldEqual, filteredString+":1234")
+			So(record.RequestHeader.Get("Authorization"), ShouldEqual, filteredString)
+			So(record.RequestBody.Size, ShouldEqual, 61)
+			So(record.RequestBody.Truncated, ShouldBeFalse)
+			So(string(record.RequestBody.Value.(json.RawMessage)), ShouldEqual, `{"age":42,"email":"*****","note":"from *****"}`)
+			So(record.Status, ShouldEqual, http.StatusCreated)
+			So(record.ResponseHeader.Get("Set-Cookie"), ShouldEqual, "session="+filteredString)
+			So(string(record.ResponseBody.Value.(json.RawMessage)), ShouldEqual, `{"age":42,"email":"*****","note":"from *****"}`)
+		})
+
+		Convey("Should decode the bodies by content type", func() {
+			handler := Middleware(f, sink, Options{})(echo)
+			for _, contentType := range []string{"application/x-www-form-urlencoded", "text/plain; charset=utf-8", "application/xml", "application/octet-stream"} {
+				body := "password=not-personal&note=" + ip
+				if contentType == "application/xml" {
+					body = "<user><password>not-personal</password><note>" + ip + "</note></user>"
+				}
+
+				request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				request.Header.Set("Content-Type", contentType)
+				handler.ServeHTTP(httptest.NewRecorder(), request)
+			}
+
+			So(records, ShouldHaveLength, 4)
+			So(records[0].RequestBody.Value, ShouldResemble, url.Values{"password": {filteredString}, "note": {filteredString}})
+			So(records[1].RequestBody.Value, ShouldEqual, "password=not-personal&note="+filteredString)
+			So(records[2].RequestBody.Value, ShouldEqual, "<user><password>*****</password><note>*****</note></user>")
+			So(records[3].RequestBody.Value, ShouldEqual, "password=not-personal&note="+filteredString)
+		})
+
+		Convey("Should keep only the first bytes of the bodies", func() {
+			handler := Middleware(f, sink, Options{MaxBodySize: 10})(echo)
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"not-personal"}`))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			So(response.Body.String(), ShouldEqual, `{"email":"not-personal"}`)
+			So(records[0].RequestBody.Size, ShouldEqual, 24)
+			So(records[0].RequestBody.Truncated, ShouldBeTrue)
+			So(records[0].RequestBody.Value, ShouldEqual, `{"email":`)
+			So(records[0].ResponseBody.Truncated, ShouldBeTrue)
+		})
+
+		Convey("Should filter the kept bytes of the truncated bodies structurally", func() {
+			handler := Middleware(f, sink, Options{MaxBodySize: 40})(echo)
+			for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+				body := `{"password":"hunter2","username":"jdoe","note":"long note"}`
+				if contentType == "application/x-www-form-urlencoded" {
+					body = "password=hunter2&username=jdoe&note=long+note+with+more+text"
+				}
+
+				request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				request.Header.Set("Content-Type", contentType)
+				handler.ServeHTTP(httptest.NewRecorder(), request)
+			}
+
+			So(records, ShouldHaveLength, 2)
+			for _, record := range records {
+				for _, body := range []Body{record.RequestBody, record.ResponseBody} {
+					So(body.Truncated, ShouldBeTrue)
+					So(body.Text(), ShouldNotContainSubstring, "hunter2")
+					So(body.Text(), ShouldNotContainSubstring, "jdoe")
+				}
+			}
+
+			So(records[0].RequestBody.Value, ShouldEqual, `{"password":"*****","username":"*****"`)
+			So(records[1].RequestBody.Value, ShouldEqual, "password=%2A%2A%2A%2A%2A&username=%2A%2A%2A%2A%2A&note=long")
+		})
+
+		Convey("Should pass the streaming responses through", func() {
+			handler := Middleware(f, sink, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte("first " + email))
+				So(err, ShouldBeNil)
+				So(http.NewResponseController(w).Flush(), ShouldBeNil)
+			}))
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			So(response.Flushed, ShouldBeTrue)
+			So(records[0].Status, ShouldEqual, http.StatusOK)
+			So(records[0].RequestBody.Value, ShouldBeNil)
+			So(records[0].ResponseBody.Value, ShouldEqual, "first "+filteredString)
+		})
+
+		Convey("Should pass the hijacked connections through", func() {
+			upgrade := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hijacker, ok := w.(http.Hijacker)
+				if !ok {
+					http.Error(w, "hijacking is not supported", http.StatusInternalServerError)
+					return
+				}
+
+				conn, rw, err := hijacker.Hijack()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				defer conn.Close()
+
+				rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+				line, _ := rw.ReadString('\n')
+				rw.WriteString(line)
+				rw.Flush()
+			})
+
+			server := httptest.NewServer(Middleware(f, func(context.Context, Record) {}, Options{})(upgrade))
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			So(err, ShouldBeNil)
+			defer conn.Close()
+
+			_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\nping\n"))
+			So(err, ShouldBeNil)
+
+			reader := bufio.NewReader(conn)
+			response, err := http.ReadResponse(reader, nil)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusSwitchingProtocols)
+
+			line, err := reader.ReadString('\n')
+			So(err, ShouldBeNil)
+			So(line, ShouldEqual, "ping\n")
+		})
+
+		Convey("Should report that hijacking is not supported by the wrapped writer", func() {
+			handler := Middleware(f, sink, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _, err := w.(http.Hijacker).Hijack()
+				So(err, ShouldEqual, http.ErrNotSupported)
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			So(records, ShouldHaveLength, 1)
+		})
+	})
+}
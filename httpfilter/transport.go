@@ -0,0 +1,166 @@
+package httpfilter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+// Dump is the filtered dump of an outbound request and its response in the format of httputil.DumpRequestOut
+// and httputil.DumpResponse. The bodies are filtered the same way as the bodies of the records.
+type Dump struct {
+	Request []byte
+	// Response is nil when the round trip failed.
+	Response []byte
+	// Error is the filtered text of the round trip error.
+	Error    string
+	Duration time.Duration
+}
+
+// DumpSink receives the dumps. The context is the one of the request.
+type DumpSink func(ctx context.Context, dump Dump)
+
+type transport struct {
+	filter  filter.PersonalDataFilter
+	next    http.RoundTripper
+	sink    DumpSink
+	options Options
+}
+
+// NewTransport returns round tripper which passes the dumps of the outbound requests to the sink. The requests
+// and the responses are passed to and from the wrapped round tripper as they are. Only the first bytes of the
+// bodies which are sent and read are kept for the dump. The dump is created when the response body is closed.
+// The dump of the 101 Switching Protocols responses is created right away and their bodies are not wrapped.
+// http.DefaultTransport is used when next is nil.
+func NewTransport(f filter.PersonalDataFilter, next http.RoundTripper, sink DumpSink, options Options) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &transport{filter: f, next: next, sink: sink, options: options}
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	requestBody := &capture{max: t.options.maxBodySize()}
+
+	// The round trippers must not modify the provided request.
+	outbound := r
+	if r.Body != nil && r.Body != http.NoBody {
+		outbound = r.WithContext(r.Context())
+		outbound.Body = &captureReader{ReadCloser: r.Body, capture: requestBody}
+	}
+
+	response, err := t.next.RoundTrip(outbound)
+	if err != nil {
+		t.sink(r.Context(), Dump{
+			Request:  t.dumpRequest(r, requestBody),
+			Error:    filter.FilterString(t.filter, err.Error()),
+			Duration: time.Since(start),
+		})
+
+		return nil, err
+	}
+
+	responseBody := &capture{max: t.options.maxBodySize()}
+	// The body of the 101 Switching Protocols response is io.ReadWriteCloser for the upgraded connection.
+	// It is returned as it is and the dump is created right away.
+	if response.StatusCode == http.StatusSwitchingProtocols {
+		t.sink(r.Context(), Dump{
+			Request:  t.dumpRequest(r, requestBody),
+			Response: t.dumpResponse(response, responseBody),
+			Duration: time.Since(start),
+		})
+
+		return response, nil
+	}
+
+	response.Body = &dumpReadCloser{
+		captureReader: captureReader{ReadCloser: response.Body, capture: responseBody},
+		dump: func() {
+			t.sink(r.Context(), Dump{
+				Request:  t.dumpRequest(r, requestBody),
+				Response: t.dumpResponse(response, responseBody),
+				Duration: time.Since(start),
+			})
+		},
+	}
+
+	return response, nil
+}
+
+func (t *transport) dumpRequest(r *http.Request, body *capture) []byte {
+	filtered := &http.Request{
+		Method:        r.Method,
+		URL:           t.filterURL(r.URL),
+		Proto:         r.Proto,
+		ProtoMajor:    r.ProtoMajor,
+		ProtoMinor:    r.ProtoMinor,
+		Header:        filterHeader(t.filter, r.Header),
+		Host:          filter.FilterString(t.filter, r.Host),
+		ContentLength: r.ContentLength,
+	}
+
+	if filtered.Header == nil {
+		filtered.Header = http.Header{}
+	}
+
+	// The body is not dumped, but httputil.DumpRequestOut needs one when the content length is not zero.
+	if r.Body != nil && r.Body != http.NoBody {
+		filtered.Body = http.NoBody
+		if r.ContentLength != 0 {
+			filtered.Body = ioutil.NopCloser(strings.NewReader(""))
+		}
+	}
+
+	dump, err := httputil.DumpRequestOut(filtered, false)
+	if err != nil {
+		dump = []byte(filter.FilterString(t.filter, err.Error()))
+	}
+
+	return append(dump, body.body(t.filter, r.Header.Get(contentTypeHeader)).Text()...)
+}
+
+func (t *transport) dumpResponse(response *http.Response, body *capture) []byte {
+	filtered := *response
+	filtered.Header = filterHeader(t.filter, response.Header)
+	filtered.Body = nil
+	filtered.Request = nil
+
+	dump, err := httputil.DumpResponse(&filtered, false)
+	if err != nil {
+		dump = []byte(filter.FilterString(t.filter, err.Error()))
+	}
+
+	return append(dump, body.body(t.filter, response.Header.Get(contentTypeHeader)).Text()...)
+}
+
+// filterURL returns the filtered URL. Only the scheme and the filtered host are kept when the filtered URL can't be parsed.
+func (t *transport) filterURL(u *url.URL) *url.URL {
+	filtered, err := url.Parse(filter.FilterURL(t.filter, u.String()))
+	if err != nil {
+		return &url.URL{Scheme: u.Scheme, Host: filter.FilterString(t.filter, u.Host), Path: "/"}
+	}
+
+	return filtered
+}
+
+// dumpReadCloser creates the dump once when the response body is closed.
+type dumpReadCloser struct {
+	captureReader
+	once sync.Once
+	dump func()
+}
+
+func (r *dumpReadCloser) Close() error {
+	err := r.captureReader.Close()
+	r.once.Do(r.dump)
+	return err
+}
@@ -0,0 +1,170 @@
+package httpfilter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+// connection is the body of the responses of upgraded connections.
+type connection struct {
+	bytes.Buffer
+}
+
+func (c *connection) Close() error {
+	return nil
+}
+
+func TestTransport(t *testing.T) {
+	Convey("Transport", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		dumps := []Dump{}
+		sink := func(ctx context.Context, dump Dump) {
+			dumps = append(dumps, dump)
+		}
+
+		received := ""
+		receivedAuthorization := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				panic(err)
+			}
+
+			received = r.URL.String() + " " + string(body)
+			receivedAuthorization = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"email":"not-personal","note":"from ` + email + `"}`))
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: NewTransport(f, nil, sink, Options{})}
+
+		Convey("Should dump the filtered request and response without changing them", func() {
+			request, err := http.NewRequest(http.MethodPost, server.URL+"/users?email=not-personal", strings.NewReader("password=not-personal&note="+ip))
+			So(err, ShouldBeNil)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			request.Header.Set("Authorization", "Bearer token")
+
+			response, err := client.Do(request)
+			So(err, ShouldBeNil)
+			body, err := ioutil.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			So(dumps, ShouldBeEmpty)
+			So(response.Body.Close(), ShouldBeNil)
+			So(response.Body.Close(), ShouldBeNil)
+
+			So(string(body), ShouldEqual, `{"email":"not-personal","note":"from `+email+`"}`)
+			So(received, ShouldEqual, "/users?email=not-personal password=not-personal&note="+ip)
+			So(receivedAuthorization, ShouldEqual, "Bearer token")
+			So(request.Header.Get("Authorization"), ShouldEqual, "Bearer token")
+
+			So(dumps, ShouldHaveLength, 1)
+			requestDump := string(dumps[0].Request)
+			So(requestDump, ShouldStartWith, "POST /users?email=%2A%2A%2A%2A%2A HTTP/1.1\r\n")
+			So(requestDump, ShouldContainSubstring, "Authorization: *****\r\n")
+			So(requestDump, ShouldEndWith, "\r\n\r\nnote=%2A%2A%2A%2A%2A&password=%2A%2A%2A%2A%2A")
+
+			responseDump := string(dumps[0].Response)
+			So(responseDump, ShouldStartWith, "HTTP/1.1 200 OK\r\n")
+			So(responseDump, ShouldContainSubstring, "Content-Type: application/json\r\n")
+			So(responseDump, ShouldEndWith, "\r\n\r\n"+`{"email":"*****","note":"from *****"}`)
+			So(dumps[0].Error, ShouldBeEmpty)
+		})
+
+		Convey("Should note the truncated bodies", func() {
+			client.Transport = NewTransport(f, nil, sink, Options{MaxBodySize: 10})
+			response, err := client.Get(server.URL)
+			So(err, ShouldBeNil)
+			_, err = ioutil.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			So(response.Body.Close(), ShouldBeNil)
+
+			So(string(dumps[0].Response), ShouldEndWith, "\r\n\r\n"+`{"email":`+"\n[truncated, 52 bytes in total]")
+		})
+
+		Convey("Should filter the kept bytes of the truncated bodies structurally", func() {
+			client.Transport = NewTransport(f, nil, sink, Options{MaxBodySize: 40})
+			for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+				body := `{"password":"hunter2","username":"jdoe","note":"long note"}`
+				if contentType == "application/x-www-form-urlencoded" {
+					body = "password=hunter2&username=jdoe&note=long+note+with+more+text"
+				}
+
+				response, err := client.Post(server.URL, contentType, strings.NewReader(body))
+				So(err, ShouldBeNil)
+				So(response.Body.Close(), ShouldBeNil)
+			}
+
+			So(dumps, ShouldHaveLength, 2)
+			So(string(dumps[0].Request), ShouldEndWith, "\r\n\r\n"+`{"password":"*****","username":"*****"`+"\n[truncated, 59 bytes in total]")
+			So(string(dumps[1].Request), ShouldEndWith, "\r\n\r\npassword=%2A%2A%2A%2A%2A&username=%2A%2A%2A%2A%2A&note=long\n[truncated, 60 bytes in total]")
+			for _, dump := range dumps {
+				So(string(dump.Request), ShouldNotContainSubstring, "hunter2")
+				So(string(dump.Request), ShouldNotContainSubstring, "jdoe")
+			}
+		})
+
+		Convey("Should not wrap the bodies of the upgraded connections", func() {
+			upgraded := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Status:     "101 Switching Protocols",
+					StatusCode: http.StatusSwitchingProtocols,
+					Proto:      "HTTP/1.1",
+					ProtoMajor: 1,
+					ProtoMinor: 1,
+					Header:     http.Header{"Upgrade": {"websocket"}, "Connection": {"Upgrade"}},
+					Body:       &connection{},
+					Request:    r,
+				}, nil
+			})
+
+			request, err := http.NewRequest(http.MethodGet, "http://example.com/chat?email=not-personal", nil)
+			So(err, ShouldBeNil)
+			request.Header.Set("Upgrade", "websocket")
+
+			response, err := NewTransport(f, upgraded, sink, Options{}).RoundTrip(request)
+			So(err, ShouldBeNil)
+			_, ok := response.Body.(io.ReadWriteCloser)
+			So(ok, ShouldBeTrue)
+
+			So(dumps, ShouldHaveLength, 1)
+			So(string(dumps[0].Request), ShouldStartWith, "GET /chat?email=%2A%2A%2A%2A%2A HTTP/1.1\r\n")
+			So(string(dumps[0].Response), ShouldStartWith, "HTTP/1.1 101 Switching Protocols\r\n")
+			So(response.Body.Close(), ShouldBeNil)
+			So(dumps, ShouldHaveLength, 1)
+		})
+
+		Convey("Should dump the failed requests", func() {
+			failing := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return nil, errors.New("can't connect to " + ip)
+			})
+
+			client.Transport = NewTransport(f, failing, sink, Options{})
+			_, err := client.Get("http://" + ip + "/users")
+			So(err, ShouldNotBeNil)
+
+			So(dumps, ShouldHaveLength, 1)
+			So(string(dumps[0].Request), ShouldStartWith, "GET /users HTTP/1.1\r\nHost: *****\r\n")
+			So(dumps[0].Response, ShouldBeNil)
+			So(dumps[0].Error, ShouldEqual, "can't connect to "+filteredString)
+		})
+	})
+}
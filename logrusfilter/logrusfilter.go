@@ -0,0 +1,46 @@
+// Package logrusfilter removes personal data from the entries logged with logrus.
+package logrusfilter
+
+import (
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook filters the message and the data of the entries in place.
+type Hook struct {
+	filter filter.PersonalDataFilter
+	levels []logrus.Level
+}
+
+// NewHook returns hook which filters the entries with the provided levels. All levels are filtered when no levels are provided.
+func NewHook(f filter.PersonalDataFilter, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+
+	return &Hook{filter: f, levels: levels}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire filters the entry. The keys of the data are used as property names.
+// logrus passes copy of the data to the hooks, so the data of the logger is not modified.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = filter.FilterString(h.filter, entry.Message)
+	for key, value := range entry.Data {
+		entry.Data[key] = h.filterValue(key, value)
+	}
+
+	return nil
+}
+
+func (h *Hook) filterValue(key string, value interface{}) interface{} {
+	// The formatters print the errors as text.
+	if err, ok := value.(error); ok {
+		return filter.FilterProperty(h.filter, key, err.Error())
+	}
+
+	return filter.FilterPropertyValue(h.filter, key, value)
+}
@@ -0,0 +1,99 @@
+package logrusfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+// constantFilter is custom filter which replaces every value.
+type constantFilter struct{}
+
+func (constantFilter) RemovePersonalData(interface{}) interface{} {
+	return filteredString
+}
+
+func TestHook(t *testing.T) {
+	Convey("Hook", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		output := new(bytes.Buffer)
+		logger := logrus.New()
+		logger.SetOutput(output)
+		logger.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+		logger.AddHook(NewHook(f))
+
+		entry := func() map[string]interface{} {
+			res := map[string]interface{}{}
+			So(json.Unmarshal(output.Bytes(), &res), ShouldBeNil)
+			output.Reset()
+			return res
+		}
+
+		Convey("Should filter the message and the data", func() {
+			logger.WithFields(logrus.Fields{
+				"email":    "not-personal",
+				"note":     "ip " + ip,
+				"userId":   42,
+				"user":     user{Name: "John", Email: "not-personal"},
+				"password": []byte("not-personal"),
+			}).WithError(errors.New("invalid " + email)).Info("user " + email + " logged in")
+
+			So(entry(), ShouldResemble, map[string]interface{}{
+				"level":    "info",
+				"msg":      "user " + filteredString + " logged in",
+				"email":    filteredString,
+				"note":     "ip " + filteredString,
+				"userId":   float64(42),
+				"user":     map[string]interface{}{"Name": "John", "Email": filteredString},
+				"password": "KioqKio=",
+				"error":    "invalid " + filteredString,
+			})
+		})
+
+		Convey("Should not modify the data of the logger entry", func() {
+			logEntry := logger.WithField("email", "not-personal")
+			logEntry.Info("message")
+			So(logEntry.Data["email"], ShouldEqual, "not-personal")
+			So(entry()["email"], ShouldEqual, filteredString)
+		})
+
+		Convey("Should keep the values when the key is filtered", func() {
+			keysFilter, err := filter.NewBuilder().SetMask(filteredString).FilterMapKeys(filter.MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			entry := &logrus.Entry{Data: logrus.Fields{ip: []string{"x", email}}}
+			So(NewHook(keysFilter).Fire(entry), ShouldBeNil)
+			So(entry.Data[ip], ShouldResemble, []string{"x", filteredString})
+		})
+
+		Convey("Should filter the values with the custom filters", func() {
+			entry := &logrus.Entry{Data: logrus.Fields{"ids": []int{1}}}
+			So(NewHook(constantFilter{}).Fire(entry), ShouldBeNil)
+			So(entry.Data["ids"], ShouldEqual, filteredString)
+		})
+
+		Convey("Should filter only the provided levels", func() {
+			hook := NewHook(f, logrus.ErrorLevel)
+			So(hook.Levels(), ShouldResemble, []logrus.Level{logrus.ErrorLevel})
+			So(NewHook(f).Levels(), ShouldResemble, logrus.AllLevels)
+		})
+	})
+}
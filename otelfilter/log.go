@@ -0,0 +1,84 @@
+package otelfilter
+
+import (
+	"context"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+type logProcessor struct {
+	filter filter.PersonalDataFilter
+	next   sdklog.Processor
+}
+
+// NewLogProcessor returns processor which passes filtered copies of the log records to the wrapped processor.
+// The bodies and the attributes of the records are filtered. The keys of the maps are treated as properties.
+// The records are copied, so the processors registered next to this one receive them unfiltered.
+func NewLogProcessor(f filter.PersonalDataFilter, next sdklog.Processor) sdklog.Processor {
+	return &logProcessor{filter: f, next: next}
+}
+
+func (p *logProcessor) Enabled(ctx context.Context, param sdklog.EnabledParameters) bool {
+	return p.next.Enabled(ctx, param)
+}
+
+func (p *logProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	filtered := FilterRecord(p.filter, record)
+	return p.next.OnEmit(ctx, &filtered)
+}
+
+func (p *logProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *logProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// FilterRecord returns filtered copy of the log record.
+func FilterRecord(f filter.PersonalDataFilter, record *sdklog.Record) sdklog.Record {
+	res := record.Clone()
+	res.SetBody(filterLogValue(f, record.Body(), false))
+
+	attrs := make([]log.KeyValue, 0, record.AttributesLen())
+	record.WalkAttributes(func(attr log.KeyValue) bool {
+		attrs = append(attrs, filterLogKeyValue(f, attr))
+		return true
+	})
+
+	res.SetAttributes(attrs...)
+	return res
+}
+
+func filterLogKeyValue(f filter.PersonalDataFilter, attr log.KeyValue) log.KeyValue {
+	return log.KeyValue{Key: attr.Key, Value: filterLogValue(f, attr.Value, isPersonalDataKey(f, attr.Key))}
+}
+
+func filterLogValue(f filter.PersonalDataFilter, value log.Value, personal bool) log.Value {
+	switch value.Kind() {
+	case log.KindString:
+		return log.StringValue(filterText(f, value.AsString(), personal))
+	case log.KindBytes:
+		return log.BytesValue([]byte(filterText(f, string(value.AsBytes()), personal)))
+	case log.KindSlice:
+		values := value.AsSlice()
+		res := make([]log.Value, len(values))
+		for i, v := range values {
+			res[i] = filterLogValue(f, v, personal)
+		}
+
+		return log.SliceValue(res...)
+	case log.KindMap:
+		attrs := value.AsMap()
+		res := make([]log.KeyValue, len(attrs))
+		for i, attr := range attrs {
+			res[i] = filterLogKeyValue(f, attr)
+		}
+
+		return log.MapValue(res...)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,99 @@
+package otelfilter
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// memoryExporter keeps the exported records in memory.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.records = append(e.records, record.Clone())
+	}
+
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) attributes(i int) []log.KeyValue {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	attrs := []log.KeyValue{}
+	e.records[i].WalkAttributes(func(attr log.KeyValue) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	return attrs
+}
+
+func TestLogProcessor(t *testing.T) {
+	Convey("NewLogProcessor", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		exporter := &memoryExporter{}
+		unfiltered := &memoryExporter{}
+		provider := sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(NewLogProcessor(f, sdklog.NewSimpleProcessor(exporter))),
+			sdklog.WithProcessor(sdklog.NewSimpleProcessor(unfiltered)),
+		)
+		logger := provider.Logger("test")
+
+		Convey("Should filter the exported records", func() {
+			record := log.Record{}
+			record.SetBody(log.StringValue("user " + email + " logged in"))
+			record.SetSeverity(log.SeverityInfo)
+			record.AddAttributes(
+				log.String("user.email", "not-personal"),
+				log.Bytes("data", []byte("ip "+ip)),
+				log.Slice("tags", log.StringValue(email), log.StringValue("tag")),
+				log.Map("user", log.String("password", "not-personal"), log.String("note", "from "+email), log.Int("age", 42)),
+				log.Int("count", 42),
+			)
+			logger.Emit(context.Background(), record)
+
+			So(exporter.records, ShouldHaveLength, 1)
+			So(exporter.records[0].Body(), ShouldResemble, log.StringValue("user "+filteredString+" logged in"))
+			So(exporter.records[0].Severity(), ShouldEqual, log.SeverityInfo)
+			So(exporter.attributes(0), ShouldResemble, []log.KeyValue{
+				log.String("user.email", filteredString),
+				log.Bytes("data", []byte("ip "+filteredString)),
+				log.Slice("tags", log.StringValue(filteredString), log.StringValue("tag")),
+				log.Map("user", log.String("password", filteredString), log.String("note", "from "+filteredString), log.Int("age", 42)),
+				log.Int("count", 42),
+			})
+
+			So(unfiltered.records[0].Body(), ShouldResemble, log.StringValue("user "+email+" logged in"))
+			So(unfiltered.attributes(0)[0], ShouldResemble, log.String("user.email", "not-personal"))
+		})
+
+		Convey("Should filter the map bodies", func() {
+			record := log.Record{}
+			record.SetBody(log.MapValue(log.String("email", "not-personal"), log.String("message", "from "+ip)))
+			logger.Emit(context.Background(), record)
+
+			So(exporter.records[0].Body(), ShouldResemble, log.MapValue(log.String("email", filteredString), log.String("message", "from "+filteredString)))
+		})
+	})
+}
@@ -0,0 +1,75 @@
+// Package otelfilter removes personal data from the OpenTelemetry spans and log records before they are exported.
+// The attribute keys are treated as properties. The dotted keys are checked as a whole, by their last part and
+// without the separators, e.g. "user.email" and "user.id" are personal data with the default configuration.
+package otelfilter
+
+import (
+	"strings"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var keySeparatorsReplacer = strings.NewReplacer(".", "", "_", "", "-", "")
+
+// FilterAttributes returns the filtered attributes. The provided slice is not modified.
+func FilterAttributes(f filter.PersonalDataFilter, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if attrs == nil {
+		return nil
+	}
+
+	res := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		res[i] = FilterAttribute(f, attr)
+	}
+
+	return res
+}
+
+// FilterAttribute filters the attribute. The string values of the attributes with personal data keys are replaced
+// with the mask and all other strings are filtered with the regular expressions. The other values are not changed.
+func FilterAttribute(f filter.PersonalDataFilter, attr attribute.KeyValue) attribute.KeyValue {
+	return attribute.KeyValue{Key: attr.Key, Value: filterAttributeValue(f, attr.Value, isPersonalDataKey(f, string(attr.Key)))}
+}
+
+func filterAttributeValue(f filter.PersonalDataFilter, value attribute.Value, personal bool) attribute.Value {
+	switch value.Type() {
+	case attribute.STRING:
+		return attribute.StringValue(filterText(f, value.AsString(), personal))
+	case attribute.STRINGSLICE:
+		values := value.AsStringSlice()
+		for i, v := range values {
+			values[i] = filterText(f, v, personal)
+		}
+
+		return attribute.StringSliceValue(values)
+	case attribute.BYTESLICE:
+		return attribute.ByteSliceValue([]byte(filterText(f, string(value.AsByteSlice()), personal)))
+	case attribute.SLICE:
+		values := value.AsSlice()
+		for i, v := range values {
+			values[i] = filterAttributeValue(f, v, personal)
+		}
+
+		return attribute.SliceValue(values...)
+	default:
+		return value
+	}
+}
+
+func filterText(f filter.PersonalDataFilter, value string, personal bool) string {
+	if personal {
+		return filter.Mask(f)
+	}
+
+	return filter.FilterString(f, value)
+}
+
+func isPersonalDataKey(f filter.PersonalDataFilter, key string) bool {
+	if filter.IsPersonalDataProperty(f, key) || filter.IsPersonalDataProperty(f, keySeparatorsReplacer.Replace(key)) {
+		return true
+	}
+
+	i := strings.LastIndexByte(key, '.')
+	return i >= 0 && filter.IsPersonalDataProperty(f, key[i+1:])
+}
@@ -0,0 +1,66 @@
+package otelfilter
+
+import (
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+func TestFilterAttributes(t *testing.T) {
+	Convey("FilterAttributes", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should return nil for nil attributes", func() {
+			So(FilterAttributes(f, nil), ShouldBeNil)
+		})
+
+		Convey("Should filter the attributes", func() {
+			input := []attribute.KeyValue{
+				attribute.String("email", "not-personal"),
+				attribute.String("user.email", "not-personal"),
+				attribute.String("user_id", "not-personal"),
+				attribute.String("http.url", "https://"+ip+"/users"),
+				attribute.StringSlice("tags", []string{email, "tag"}),
+				attribute.StringSlice("client.ip", []string{"not-personal"}),
+				attribute.ByteSlice("data", []byte("ip "+ip)),
+				attribute.Slice("values", attribute.StringValue(email), attribute.IntValue(42)),
+				attribute.Int("count", 42),
+				attribute.Bool("enabled", true),
+			}
+
+			So(FilterAttributes(f, input), ShouldResemble, []attribute.KeyValue{
+				attribute.String("email", filteredString),
+				attribute.String("user.email", filteredString),
+				attribute.String("user_id", filteredString),
+				attribute.String("http.url", "https://"+filteredString+"/users"),
+				attribute.StringSlice("tags", []string{filteredString, "tag"}),
+				attribute.StringSlice("client.ip", []string{filteredString}),
+				attribute.ByteSlice("data", []byte("ip "+filteredString)),
+				attribute.Slice("values", attribute.StringValue(filteredString), attribute.IntValue(42)),
+				attribute.Int("count", 42),
+				attribute.Bool("enabled", true),
+			})
+		})
+
+		Convey("Should not modify the input", func() {
+			input := []attribute.KeyValue{attribute.StringSlice("tags", []string{email})}
+			FilterAttributes(f, input)
+			So(input[0].Value.AsStringSlice(), ShouldResemble, []string{email})
+		})
+
+		Convey("Should use the additional properties", func() {
+			f, err := filter.NewBuilder().SetMask(filteredString).AddPersonalDataProperties("enduser.id").Build()
+			So(err, ShouldBeNil)
+			So(FilterAttribute(f, attribute.String("enduser.id", "john")), ShouldResemble, attribute.String("enduser.id", filteredString))
+		})
+	})
+}
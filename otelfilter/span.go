@@ -0,0 +1,95 @@
+package otelfilter
+
+import (
+	"context"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type spanProcessor struct {
+	filter filter.PersonalDataFilter
+	next   sdktrace.SpanProcessor
+}
+
+// NewSpanProcessor returns processor which passes filtered copies of the ended spans to the wrapped processor.
+// The spans can't be modified when they are ended, so the exporting processor must be wrapped instead of being
+// registered next to this one. The names, the attributes, the events, the links and the status descriptions
+// of the spans are filtered. The started spans are passed to the wrapped processor as they are.
+func NewSpanProcessor(f filter.PersonalDataFilter, next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	return &spanProcessor{filter: f, next: next}
+}
+
+func (p *spanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *spanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(FilterSpan(p.filter, s))
+}
+
+func (p *spanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *spanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// filteredSpan overrides the methods of the span which return personal data. The embedded span provides the other
+// methods, including the unexported one which can't be implemented outside of the SDK.
+type filteredSpan struct {
+	sdktrace.ReadOnlySpan
+	name       string
+	attributes []attribute.KeyValue
+	links      []sdktrace.Link
+	events     []sdktrace.Event
+	status     sdktrace.Status
+}
+
+// FilterSpan returns filtered copy of the span. The span events are filtered the same way as the spans.
+func FilterSpan(f filter.PersonalDataFilter, s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	links := append([]sdktrace.Link(nil), s.Links()...)
+	for i := range links {
+		links[i].Attributes = FilterAttributes(f, links[i].Attributes)
+	}
+
+	events := append([]sdktrace.Event(nil), s.Events()...)
+	for i := range events {
+		events[i].Name = filter.FilterString(f, events[i].Name)
+		events[i].Attributes = FilterAttributes(f, events[i].Attributes)
+	}
+
+	status := s.Status()
+	status.Description = filter.FilterString(f, status.Description)
+
+	return &filteredSpan{
+		ReadOnlySpan: s,
+		name:         filter.FilterString(f, s.Name()),
+		attributes:   FilterAttributes(f, s.Attributes()),
+		links:        links,
+		events:       events,
+		status:       status,
+	}
+}
+
+func (s *filteredSpan) Name() string {
+	return s.name
+}
+
+func (s *filteredSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+func (s *filteredSpan) Links() []sdktrace.Link {
+	return s.links
+}
+
+func (s *filteredSpan) Events() []sdktrace.Event {
+	return s.events
+}
+
+func (s *filteredSpan) Status() sdktrace.Status {
+	return s.status
+}
@@ -0,0 +1,74 @@
+package otelfilter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanProcessor(t *testing.T) {
+	Convey("NewSpanProcessor", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		exporter := tracetest.NewInMemoryExporter()
+		unfiltered := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(NewSpanProcessor(f, sdktrace.NewSimpleSpanProcessor(exporter))),
+			sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(unfiltered)),
+		)
+		tracer := provider.Tracer("test")
+
+		Convey("Should filter the exported spans", func() {
+			ctx, parent := tracer.Start(context.Background(), "parent")
+			_, span := tracer.Start(ctx, "GET user "+email,
+				trace.WithAttributes(attribute.String("user.email", "not-personal"), attribute.String("db.statement", "SELECT * FROM users WHERE ip = '"+ip+"'")),
+				trace.WithLinks(trace.Link{SpanContext: parent.SpanContext(), Attributes: []attribute.KeyValue{attribute.String("note", email)}}),
+			)
+			span.AddEvent("sent to "+email, trace.WithAttributes(attribute.String("password", "not-personal")))
+			span.RecordError(errors.New("user " + email + " not found"))
+			span.SetStatus(codes.Error, "invalid "+email)
+			span.End()
+			parent.End()
+
+			spans := exporter.GetSpans()
+			So(spans, ShouldHaveLength, 2)
+
+			res := spans[0]
+			So(res.Name, ShouldEqual, "GET user "+filteredString)
+			So(res.Attributes, ShouldResemble, []attribute.KeyValue{
+				attribute.String("user.email", filteredString),
+				attribute.String("db.statement", "SELECT * FROM users WHERE ip = '"+filteredString+"'"),
+			})
+			So(res.Links[0].SpanContext, ShouldResemble, parent.SpanContext())
+			So(res.Links[0].Attributes, ShouldResemble, []attribute.KeyValue{attribute.String("note", filteredString)})
+			So(res.Events, ShouldHaveLength, 2)
+			So(res.Events[0].Name, ShouldEqual, "sent to "+filteredString)
+			So(res.Events[0].Attributes, ShouldResemble, []attribute.KeyValue{attribute.String("password", filteredString)})
+			So(res.Events[1].Attributes, ShouldContain, attribute.String("exception.message", "user "+filteredString+" not found"))
+			So(res.Status, ShouldResemble, sdktrace.Status{Code: codes.Error, Description: "invalid " + filteredString})
+			So(res.SpanContext, ShouldResemble, span.SpanContext())
+			So(res.Parent, ShouldResemble, parent.SpanContext())
+
+			So(unfiltered.GetSpans()[0].Name, ShouldEqual, "GET user "+email)
+			So(unfiltered.GetSpans()[0].Attributes[0], ShouldResemble, attribute.String("user.email", "not-personal"))
+		})
+
+		Convey("Should pass the shutdown to the wrapped processor", func() {
+			So(provider.ForceFlush(context.Background()), ShouldBeNil)
+			So(provider.Shutdown(context.Background()), ShouldBeNil)
+
+			_, span := tracer.Start(context.Background(), "span")
+			span.End()
+			So(exporter.GetSpans(), ShouldBeEmpty)
+		})
+	})
+}
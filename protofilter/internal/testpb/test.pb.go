@@ -0,0 +1,383 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: protofilter/internal/testpb/test.proto
+
+package testpb
+
+import (
+	_ "github.com/Icenium/go-personal-data-filter/protofilter/pdfilterpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email        string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	UserEmail    string                 `protobuf:"bytes,3,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	Note         string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	Secret       string                 `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	SecretNumber int64                  `protobuf:"varint,6,opt,name=secret_number,json=secretNumber,proto3" json:"secret_number,omitempty"`
+	Data         []byte                 `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+	Tags         []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Labels       map[string]string      `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Address      *Address               `protobuf:"bytes,10,opt,name=address,proto3" json:"address,omitempty"`
+	Addresses    []*Address             `protobuf:"bytes,11,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Details      *anypb.Any             `protobuf:"bytes,12,opt,name=details,proto3" json:"details,omitempty"`
+	Metadata     *structpb.Struct       `protobuf:"bytes,13,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Age          int32                  `protobuf:"varint,14,opt,name=age,proto3" json:"age,omitempty"`
+	// Types that are valid to be assigned to Contact:
+	//
+	//	*User_Ip
+	//	*User_Phone
+	//	*User_Home
+	Contact       isUser_Contact `protobuf_oneof:"contact"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_protofilter_internal_testpb_test_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_protofilter_internal_testpb_test_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_protofilter_internal_testpb_test_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetUserEmail() string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return ""
+}
+
+func (x *User) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *User) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *User) GetSecretNumber() int64 {
+	if x != nil {
+		return x.SecretNumber
+	}
+	return 0
+}
+
+func (x *User) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *User) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *User) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *User) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *User) GetAddresses() []*Address {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *User) GetDetails() *anypb.Any {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *User) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *User) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *User) GetContact() isUser_Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *User) GetIp() string {
+	if x != nil {
+		if x, ok := x.Contact.(*User_Ip); ok {
+			return x.Ip
+		}
+	}
+	return ""
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		if x, ok := x.Contact.(*User_Phone); ok {
+			return x.Phone
+		}
+	}
+	return ""
+}
+
+func (x *User) GetHome() *Address {
+	if x != nil {
+		if x, ok := x.Contact.(*User_Home); ok {
+			return x.Home
+		}
+	}
+	return nil
+}
+
+type isUser_Contact interface {
+	isUser_Contact()
+}
+
+type User_Ip struct {
+	Ip string `protobuf:"bytes,15,opt,name=ip,proto3,oneof"`
+}
+
+type User_Phone struct {
+	Phone string `protobuf:"bytes,16,opt,name=phone,proto3,oneof"`
+}
+
+type User_Home struct {
+	Home *Address `protobuf:"bytes,17,opt,name=home,proto3,oneof"`
+}
+
+func (*User_Ip) isUser_Contact() {}
+
+func (*User_Phone) isUser_Contact() {}
+
+func (*User_Home) isUser_Contact() {}
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Street        string                 `protobuf:"bytes,1,opt,name=street,proto3" json:"street,omitempty"`
+	Account       string                 `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_protofilter_internal_testpb_test_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_protofilter_internal_testpb_test_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_protofilter_internal_testpb_test_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Address) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *Address) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+var File_protofilter_internal_testpb_test_proto protoreflect.FileDescriptor
+
+const file_protofilter_internal_testpb_test_proto_rawDesc = "" +
+	"\n" +
+	"&protofilter/internal/testpb/test.proto\x12\rpdfilter.test\x1a\x19google/protobuf/any.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a$protofilter/pdfilterpb/options.proto\"\x90\x05\n" +
+	"\x04User\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"user_email\x18\x03 \x01(\tR\tuserEmail\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\x12\x1c\n" +
+	"\x06secret\x18\x05 \x01(\tB\x04\xe8\xe0\x18\x01R\x06secret\x12)\n" +
+	"\rsecret_number\x18\x06 \x01(\x03B\x04\xe8\xe0\x18\x01R\fsecretNumber\x12\x12\n" +
+	"\x04data\x18\a \x01(\fR\x04data\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x127\n" +
+	"\x06labels\x18\t \x03(\v2\x1f.pdfilter.test.User.LabelsEntryR\x06labels\x120\n" +
+	"\aaddress\x18\n" +
+	" \x01(\v2\x16.pdfilter.test.AddressR\aaddress\x124\n" +
+	"\taddresses\x18\v \x03(\v2\x16.pdfilter.test.AddressR\taddresses\x12.\n" +
+	"\adetails\x18\f \x01(\v2\x14.google.protobuf.AnyR\adetails\x123\n" +
+	"\bmetadata\x18\r \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x10\n" +
+	"\x03age\x18\x0e \x01(\x05R\x03age\x12\x10\n" +
+	"\x02ip\x18\x0f \x01(\tH\x00R\x02ip\x12\x16\n" +
+	"\x05phone\x18\x10 \x01(\tH\x00R\x05phone\x122\n" +
+	"\x04home\x18\x11 \x01(\v2\x16.pdfilter.test.AddressB\x04\xe8\xe0\x18\x01H\x00R\x04home\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\acontact\";\n" +
+	"\aAddress\x12\x16\n" +
+	"\x06street\x18\x01 \x01(\tR\x06street\x12\x18\n" +
+	"\aaccount\x18\x02 \x01(\tR\aaccountBHZFgithub.com/Icenium/go-personal-data-filter/protofilter/internal/testpbb\x06proto3"
+
+var (
+	file_protofilter_internal_testpb_test_proto_rawDescOnce sync.Once
+	file_protofilter_internal_testpb_test_proto_rawDescData []byte
+)
+
+func file_protofilter_internal_testpb_test_proto_rawDescGZIP() []byte {
+	file_protofilter_internal_testpb_test_proto_rawDescOnce.Do(func() {
+		file_protofilter_internal_testpb_test_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protofilter_internal_testpb_test_proto_rawDesc), len(file_protofilter_internal_testpb_test_proto_rawDesc)))
+	})
+	return file_protofilter_internal_testpb_test_proto_rawDescData
+}
+
+var file_protofilter_internal_testpb_test_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_protofilter_internal_testpb_test_proto_goTypes = []any{
+	(*User)(nil),            // 0: pdfilter.test.User
+	(*Address)(nil),         // 1: pdfilter.test.Address
+	nil,                     // 2: pdfilter.test.User.LabelsEntry
+	(*anypb.Any)(nil),       // 3: google.protobuf.Any
+	(*structpb.Struct)(nil), // 4: google.protobuf.Struct
+}
+var file_protofilter_internal_testpb_test_proto_depIdxs = []int32{
+	2, // 0: pdfilter.test.User.labels:type_name -> pdfilter.test.User.LabelsEntry
+	1, // 1: pdfilter.test.User.address:type_name -> pdfilter.test.Address
+	1, // 2: pdfilter.test.User.addresses:type_name -> pdfilter.test.Address
+	3, // 3: pdfilter.test.User.details:type_name -> google.protobuf.Any
+	4, // 4: pdfilter.test.User.metadata:type_name -> google.protobuf.Struct
+	1, // 5: pdfilter.test.User.home:type_name -> pdfilter.test.Address
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_protofilter_internal_testpb_test_proto_init() }
+func file_protofilter_internal_testpb_test_proto_init() {
+	if File_protofilter_internal_testpb_test_proto != nil {
+		return
+	}
+	file_protofilter_internal_testpb_test_proto_msgTypes[0].OneofWrappers = []any{
+		(*User_Ip)(nil),
+		(*User_Phone)(nil),
+		(*User_Home)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protofilter_internal_testpb_test_proto_rawDesc), len(file_protofilter_internal_testpb_test_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protofilter_internal_testpb_test_proto_goTypes,
+		DependencyIndexes: file_protofilter_internal_testpb_test_proto_depIdxs,
+		MessageInfos:      file_protofilter_internal_testpb_test_proto_msgTypes,
+	}.Build()
+	File_protofilter_internal_testpb_test_proto = out.File
+	file_protofilter_internal_testpb_test_proto_goTypes = nil
+	file_protofilter_internal_testpb_test_proto_depIdxs = nil
+}
@@ -0,0 +1,85 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: protofilter/pdfilterpb/options.proto
+
+package pdfilterpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_protofilter_pdfilterpb_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50701,
+		Name:          "pdfilter.sensitive",
+		Tag:           "varint,50701,opt,name=sensitive",
+		Filename:      "protofilter/pdfilterpb/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// sensitive marks the field as personal data. The string and bytes values are replaced with the mask
+	// and all other values are cleared.
+	//
+	// optional bool sensitive = 50701;
+	E_Sensitive = &file_protofilter_pdfilterpb_options_proto_extTypes[0]
+)
+
+var File_protofilter_pdfilterpb_options_proto protoreflect.FileDescriptor
+
+const file_protofilter_pdfilterpb_options_proto_rawDesc = "" +
+	"\n" +
+	"$protofilter/pdfilterpb/options.proto\x12\bpdfilter\x1a google/protobuf/descriptor.proto:=\n" +
+	"\tsensitive\x12\x1d.google.protobuf.FieldOptions\x18\x8d\x8c\x03 \x01(\bR\tsensitiveBCZAgithub.com/Icenium/go-personal-data-filter/protofilter/pdfilterpbb\x06proto3"
+
+var file_protofilter_pdfilterpb_options_proto_goTypes = []any{
+	(*descriptorpb.FieldOptions)(nil), // 0: google.protobuf.FieldOptions
+}
+var file_protofilter_pdfilterpb_options_proto_depIdxs = []int32{
+	0, // 0: pdfilter.sensitive:extendee -> google.protobuf.FieldOptions
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_protofilter_pdfilterpb_options_proto_init() }
+func file_protofilter_pdfilterpb_options_proto_init() {
+	if File_protofilter_pdfilterpb_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protofilter_pdfilterpb_options_proto_rawDesc), len(file_protofilter_pdfilterpb_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_protofilter_pdfilterpb_options_proto_goTypes,
+		DependencyIndexes: file_protofilter_pdfilterpb_options_proto_depIdxs,
+		ExtensionInfos:    file_protofilter_pdfilterpb_options_proto_extTypes,
+	}.Build()
+	File_protofilter_pdfilterpb_options_proto = out.File
+	file_protofilter_pdfilterpb_options_proto_goTypes = nil
+	file_protofilter_pdfilterpb_options_proto_depIdxs = nil
+}
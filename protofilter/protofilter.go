@@ -0,0 +1,207 @@
+// Package protofilter removes personal data from Protocol Buffers messages. The messages are walked
+// with protoreflect, so the proto field names are used as property names and the internal fields
+// of the generated structs are not touched.
+//
+// The fields can be marked as personal data with the sensitive option:
+//
+//	import "protofilter/pdfilterpb/options.proto";
+//
+//	message User {
+//	  string token = 1 [(pdfilter.sensitive) = true];
+//	}
+package protofilter
+
+//go:generate protoc -I.. --go_out=.. --go_opt=paths=source_relative ../protofilter/pdfilterpb/options.proto ../protofilter/internal/testpb/test.proto
+
+import (
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/Icenium/go-personal-data-filter/protofilter/pdfilterpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	anyFullName    protoreflect.FullName = "google.protobuf.Any"
+	structFullName protoreflect.FullName = "google.protobuf.Struct"
+
+	anyTypeURLField     protoreflect.Name = "type_url"
+	anyValueField       protoreflect.Name = "value"
+	structFieldsField   protoreflect.Name = "fields"
+	valueStringField    protoreflect.Name = "string_value"
+	valueKindOneofField protoreflect.Name = "kind"
+)
+
+// FilterMessage returns filtered copy of the message. The values of the fields with personal data
+// property names or with the sensitive option are replaced with the mask. The sensitive fields which
+// are not strings or bytes are cleared. All other strings and bytes are filtered with the regular expressions.
+//
+// The messages packed in Any are filtered when their types are registered. Otherwise their values
+// are cleared. The unknown fields are cleared too, because they can't be inspected.
+func FilterMessage(f filter.PersonalDataFilter, m proto.Message) proto.Message {
+	if m == nil {
+		return nil
+	}
+
+	res := proto.Clone(m)
+	filterMessage(f, res.ProtoReflect())
+	return res
+}
+
+// IsSensitiveField checks if the field is marked with the sensitive option.
+func IsSensitiveField(fd protoreflect.FieldDescriptor) bool {
+	options, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || options == nil {
+		return false
+	}
+
+	return proto.GetExtension(options, pdfilterpb.E_Sensitive).(bool)
+}
+
+func filterMessage(f filter.PersonalDataFilter, m protoreflect.Message) {
+	if !m.IsValid() {
+		return
+	}
+
+	switch m.Descriptor().FullName() {
+	case anyFullName:
+		filterAny(f, m)
+		return
+	case structFullName:
+		filterStruct(f, m)
+		return
+	}
+
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+
+	// The message can't be modified while its fields are ranged.
+	fields := []protoreflect.FieldDescriptor{}
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+
+	for _, fd := range fields {
+		filterField(f, m, fd)
+	}
+}
+
+func filterField(f filter.PersonalDataFilter, m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	sensitive := IsSensitiveField(fd)
+	if sensitive && !isText(fd) {
+		m.Clear(fd)
+		return
+	}
+
+	personal := sensitive || filter.IsPersonalDataProperty(f, string(fd.Name())) || filter.IsPersonalDataProperty(f, fd.JSONName())
+	switch {
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			list.Set(i, filterValue(f, fd, list.Get(i), personal))
+		}
+	case fd.IsMap():
+		filterMap(f, fd, m.Mutable(fd).Map())
+	case isMessage(fd):
+		filterMessage(f, m.Mutable(fd).Message())
+	default:
+		m.Set(fd, filterValue(f, fd, m.Get(fd), personal))
+	}
+}
+
+// filterMap uses the string keys of the map as property names.
+func filterMap(f filter.PersonalDataFilter, fd protoreflect.FieldDescriptor, mapValue protoreflect.Map) {
+	keys := []protoreflect.MapKey{}
+	mapValue.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	for _, key := range keys {
+		personal := fd.MapKey().Kind() == protoreflect.StringKind && filter.IsPersonalDataProperty(f, key.String())
+		mapValue.Set(key, filterValue(f, fd.MapValue(), mapValue.Get(key), personal))
+	}
+}
+
+func filterValue(f filter.PersonalDataFilter, fd protoreflect.FieldDescriptor, value protoreflect.Value, personal bool) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if personal {
+			return protoreflect.ValueOfString(filter.Mask(f))
+		}
+
+		return protoreflect.ValueOfString(filter.FilterString(f, value.String()))
+	case protoreflect.BytesKind:
+		if personal {
+			return protoreflect.ValueOfBytes([]byte(filter.Mask(f)))
+		}
+
+		return protoreflect.ValueOfBytes([]byte(filter.FilterString(f, string(value.Bytes()))))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		filterMessage(f, value.Message())
+	}
+
+	return value
+}
+
+// filterAny filters the packed message. The value is cleared if the message can't be unpacked.
+func filterAny(f filter.PersonalDataFilter, m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	typeURLField, valueField := fields.ByName(anyTypeURLField), fields.ByName(anyValueField)
+	if !m.Has(valueField) {
+		return
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(m.Get(typeURLField).String())
+	if err != nil {
+		m.Clear(valueField)
+		return
+	}
+
+	packed := messageType.New()
+	if err := proto.Unmarshal(m.Get(valueField).Bytes(), packed.Interface()); err != nil {
+		m.Clear(valueField)
+		return
+	}
+
+	filterMessage(f, packed)
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(packed.Interface())
+	if err != nil {
+		m.Clear(valueField)
+		return
+	}
+
+	m.Set(valueField, protoreflect.ValueOfBytes(value))
+}
+
+// filterStruct uses the keys of the Struct as property names, the same way the keys of map[string]interface{} are used.
+func filterStruct(f filter.PersonalDataFilter, m protoreflect.Message) {
+	fieldsField := m.Descriptor().Fields().ByName(structFieldsField)
+	if !m.Has(fieldsField) {
+		return
+	}
+
+	fields := m.Mutable(fieldsField).Map()
+	fields.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+		structValue := value.Message()
+		kind := structValue.WhichOneof(structValue.Descriptor().Oneofs().ByName(valueKindOneofField))
+		if kind != nil && kind.Name() == valueStringField && filter.IsPersonalDataProperty(f, key.String()) {
+			structValue.Set(kind, protoreflect.ValueOfString(filter.Mask(f)))
+		} else {
+			filterMessage(f, structValue)
+		}
+
+		return true
+	})
+}
+
+func isText(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsMap() && (fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind)
+}
+
+func isMessage(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind
+}
@@ -0,0 +1,145 @@
+package protofilter
+
+import (
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/Icenium/go-personal-data-filter/protofilter/internal/testpb"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+func TestFilterMessage(t *testing.T) {
+	Convey("FilterMessage", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should return nil for nil message", func() {
+			So(FilterMessage(f, nil), ShouldBeNil)
+		})
+
+		Convey("Should not modify the input", func() {
+			input := &testpb.User{Email: email}
+			FilterMessage(f, input)
+			So(input.Email, ShouldEqual, email)
+		})
+
+		Convey("Should filter the scalar fields", func() {
+			input := &testpb.User{
+				Name:      "John",
+				Email:     "not-personal",
+				UserEmail: "not-personal",
+				Note:      "from " + email,
+				Data:      []byte("ip " + ip),
+				Age:       42,
+			}
+
+			expected := &testpb.User{
+				Name:      "John",
+				Email:     filteredString,
+				UserEmail: filteredString,
+				Note:      "from " + filteredString,
+				Data:      []byte("ip " + filteredString),
+				Age:       42,
+			}
+
+			So(proto.Equal(FilterMessage(f, input), expected), ShouldBeTrue)
+		})
+
+		Convey("Should mask the sensitive fields and clear the non-text ones", func() {
+			input := &testpb.User{Secret: "not-personal", SecretNumber: 42}
+			expected := &testpb.User{Secret: filteredString}
+			So(proto.Equal(FilterMessage(f, input), expected), ShouldBeTrue)
+		})
+
+		Convey("Should filter the nested messages, the lists and the maps", func() {
+			input := &testpb.User{
+				Tags:      []string{email, "tag"},
+				Labels:    map[string]string{"password": "not-personal", "note": ip},
+				Address:   &testpb.Address{Street: email, Account: "not-personal"},
+				Addresses: []*testpb.Address{{Street: "street", Account: "not-personal"}},
+			}
+
+			expected := &testpb.User{
+				Tags:      []string{filteredString, "tag"},
+				Labels:    map[string]string{"password": filteredString, "note": filteredString},
+				Address:   &testpb.Address{Street: filteredString, Account: filteredString},
+				Addresses: []*testpb.Address{{Street: "street", Account: filteredString}},
+			}
+
+			So(proto.Equal(FilterMessage(f, input), expected), ShouldBeTrue)
+		})
+
+		Convey("Should filter the oneof fields", func() {
+			input := &testpb.User{Contact: &testpb.User_Ip{Ip: "not-personal"}}
+			expected := &testpb.User{Contact: &testpb.User_Ip{Ip: filteredString}}
+			So(proto.Equal(FilterMessage(f, input), expected), ShouldBeTrue)
+
+			input = &testpb.User{Contact: &testpb.User_Phone{Phone: "phone " + ip}}
+			expected = &testpb.User{Contact: &testpb.User_Phone{Phone: "phone " + filteredString}}
+			So(proto.Equal(FilterMessage(f, input), expected), ShouldBeTrue)
+
+			input = &testpb.User{Contact: &testpb.User_Home{Home: &testpb.Address{Street: "street"}}}
+			So(proto.Equal(FilterMessage(f, input), &testpb.User{}), ShouldBeTrue)
+		})
+
+		Convey("Should filter the Any fields", func() {
+			details, err := anypb.New(&testpb.Address{Street: email, Account: "not-personal"})
+			So(err, ShouldBeNil)
+
+			res := FilterMessage(f, &testpb.User{Details: details}).(*testpb.User)
+			address := &testpb.Address{}
+			So(res.Details.UnmarshalTo(address), ShouldBeNil)
+			So(proto.Equal(address, &testpb.Address{Street: filteredString, Account: filteredString}), ShouldBeTrue)
+		})
+
+		Convey("Should clear the value of Any with unknown type", func() {
+			details := &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Type", Value: []byte(email)}
+			res := FilterMessage(f, &testpb.User{Details: details}).(*testpb.User)
+			So(res.Details.TypeUrl, ShouldEqual, details.TypeUrl)
+			So(res.Details.Value, ShouldBeEmpty)
+		})
+
+		Convey("Should filter the Struct fields", func() {
+			metadata, err := structpb.NewStruct(map[string]interface{}{
+				"email":  "not-personal",
+				"userId": 42,
+				"note":   "from " + email,
+				"nested": map[string]interface{}{"password": "not-personal"},
+				"list":   []interface{}{ip, "text"},
+			})
+			So(err, ShouldBeNil)
+
+			res := FilterMessage(f, &testpb.User{Metadata: metadata}).(*testpb.User)
+			So(res.Metadata.AsMap(), ShouldResemble, map[string]interface{}{
+				"email":  filteredString,
+				"userId": float64(42),
+				"note":   "from " + filteredString,
+				"nested": map[string]interface{}{"password": filteredString},
+				"list":   []interface{}{filteredString, "text"},
+			})
+		})
+
+		Convey("Should clear the unknown fields", func() {
+			input := &testpb.Address{Street: "street"}
+			input.ProtoReflect().SetUnknown([]byte{0xa2, 0x06, 0x03, 'a', 'b', 'c'})
+			res := FilterMessage(f, input)
+			So(res.ProtoReflect().GetUnknown(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("IsSensitiveField", t, func() {
+		fields := (&testpb.User{}).ProtoReflect().Descriptor().Fields()
+		So(IsSensitiveField(fields.ByName("secret")), ShouldBeTrue)
+		So(IsSensitiveField(fields.ByName("home")), ShouldBeTrue)
+		So(IsSensitiveField(fields.ByName("email")), ShouldBeFalse)
+	})
+}
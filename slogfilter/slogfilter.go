@@ -0,0 +1,73 @@
+// Package slogfilter removes personal data from log/slog records before they reach the wrapped handler.
+package slogfilter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+// Handler filters the message and the attributes of the records and passes them to the wrapped handler.
+type Handler struct {
+	filter  filter.PersonalDataFilter
+	handler slog.Handler
+}
+
+// NewHandler returns handler which filters the records with the provided filter.
+func NewHandler(f filter.PersonalDataFilter, handler slog.Handler) *Handler {
+	return &Handler{filter: f, handler: handler}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	filtered := slog.NewRecord(record.Time, record.Level, filter.FilterString(h.filter, record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		filtered.AddAttrs(FilterAttr(h.filter, attr))
+		return true
+	})
+
+	return h.handler.Handle(ctx, filtered)
+}
+
+// WithAttrs filters the attributes once. They are not filtered again when the records are handled.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{filter: h.filter, handler: h.handler.WithAttrs(filterAttrs(h.filter, attrs))}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{filter: h.filter, handler: h.handler.WithGroup(name)}
+}
+
+// FilterAttr filters the attribute. The key is used as property name. The values of the LogValuer
+// attributes are resolved and the groups are filtered recursively.
+func FilterAttr(f filter.PersonalDataFilter, attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, filter.FilterProperty(f, attr.Key, value.String()))
+	case slog.KindGroup:
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(filterAttrs(f, value.Group())...)}
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			// The handlers print the errors as text.
+			return slog.String(attr.Key, filter.FilterProperty(f, attr.Key, err.Error()))
+		}
+
+		return slog.Any(attr.Key, filter.FilterPropertyValue(f, attr.Key, value.Any()))
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+func filterAttrs(f filter.PersonalDataFilter, attrs []slog.Attr) []slog.Attr {
+	res := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		res[i] = FilterAttr(f, attr)
+	}
+
+	return res
+}
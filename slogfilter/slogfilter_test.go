@@ -0,0 +1,150 @@
+package slogfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+type userValuer struct {
+	email string
+}
+
+func (v userValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("email", v.email), slog.String("note", "from "+v.email))
+}
+
+// countingValuer counts how many times its value is resolved.
+type countingValuer struct {
+	count *int
+}
+
+func (v countingValuer) LogValue() slog.Value {
+	*v.count++
+	return slog.StringValue(email)
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Handler", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		output := new(bytes.Buffer)
+		logger := slog.New(NewHandler(f, slog.NewJSONHandler(output, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+				if len(groups) == 0 && attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+
+				return attr
+			},
+		})))
+
+		record := func() map[string]interface{} {
+			res := map[string]interface{}{}
+			So(json.Unmarshal(output.Bytes(), &res), ShouldBeNil)
+			output.Reset()
+			return res
+		}
+
+		Convey("Should filter the message and the attributes", func() {
+			logger.Info("user "+email+" logged in",
+				slog.String("email", "not-personal"),
+				slog.String("note", "ip "+ip),
+				slog.Int("userId", 42),
+				slog.Any("user", user{Name: "John", Email: "not-personal"}),
+				slog.Any("password", []byte("not-personal")),
+				slog.Any("error", errors.New("invalid "+email)),
+			)
+
+			So(record(), ShouldResemble, map[string]interface{}{
+				"level":    "INFO",
+				"msg":      "user " + filteredString + " logged in",
+				"email":    filteredString,
+				"note":     "ip " + filteredString,
+				"userId":   float64(42),
+				"user":     map[string]interface{}{"Name": "John", "Email": filteredString},
+				"password": "KioqKio=",
+				"error":    "invalid " + filteredString,
+			})
+		})
+
+		Convey("Should filter the groups and the LogValuer results", func() {
+			logger.Info("message", slog.Group("request", slog.String("ip", "not-personal"), slog.Any("user", userValuer{email: email})))
+
+			So(record(), ShouldResemble, map[string]interface{}{
+				"level": "INFO",
+				"msg":   "message",
+				"request": map[string]interface{}{
+					"ip":   filteredString,
+					"user": map[string]interface{}{"email": filteredString, "note": "from " + filteredString},
+				},
+			})
+		})
+
+		Convey("Should filter the attributes of WithAttrs and WithGroup once", func() {
+			count := 0
+			child := logger.With(slog.Any("note", countingValuer{count: &count})).WithGroup("group").With(slog.String("email", "not-personal"))
+			child.Info("first", slog.String("ip", "not-personal"))
+			child.Info("second")
+			So(count, ShouldEqual, 1)
+
+			output.Reset()
+			child.Info("third", slog.String("ip", "not-personal"))
+			So(record(), ShouldResemble, map[string]interface{}{
+				"level": "INFO",
+				"msg":   "third",
+				"note":  filteredString,
+				"group": map[string]interface{}{"email": filteredString, "ip": filteredString},
+			})
+		})
+
+		Convey("Should respect the level of the wrapped handler", func() {
+			So(logger.Handler().Enabled(context.Background(), slog.LevelDebug), ShouldBeFalse)
+			logger.Debug("message")
+			So(output.Len(), ShouldEqual, 0)
+		})
+	})
+}
+
+// constantFilter is custom filter which replaces every value.
+type constantFilter struct{}
+
+func (constantFilter) RemovePersonalData(interface{}) interface{} {
+	return filteredString
+}
+
+func TestFilterAttr(t *testing.T) {
+	Convey("FilterAttr", t, func() {
+		Convey("Should keep the values when the key is filtered", func() {
+			f, err := filter.NewBuilder().SetMask(filteredString).FilterMapKeys(filter.MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			attr := FilterAttr(f, slog.Any(ip, []string{"x", email}))
+			So(attr.Key, ShouldEqual, ip)
+			So(attr.Value.Any(), ShouldResemble, []string{"x", filteredString})
+		})
+
+		Convey("Should filter the values with the custom filters", func() {
+			attr := FilterAttr(constantFilter{}, slog.Any("note", []int{1}))
+			So(attr.Value.Any(), ShouldEqual, filteredString)
+		})
+	})
+}
@@ -0,0 +1,230 @@
+package sqlfilter
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+var (
+	errNamedArgs = errors.New("sqlfilter: the driver does not support named arguments")
+	errTxOptions = errors.New("sqlfilter: the driver does not support non-default transaction options")
+)
+
+// conn implements the optional interfaces of database/sql. They return driver.ErrSkip or fall back
+// to the required methods when the wrapped connection doesn't implement them, the same way database/sql does.
+type conn struct {
+	conn     driver.Conn
+	filter   filter.PersonalDataFilter
+	callback Callback
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{stmt: s, query: query, conn: c}, nil
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	preparer, ok := c.conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+
+	s, err := preparer.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{stmt: s, query: query, conn: c}, nil
+}
+
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin is deprecated, but it is required by driver.Conn.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
+		return nil, errTxOptions
+	}
+
+	return c.Begin()
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		report(ctx, c.filter, c.callback, query, args, start, err)
+	}
+
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		report(ctx, c.filter, c.callback, query, args, start, err)
+	}
+
+	return rows, err
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *conn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+type stmt struct {
+	stmt  driver.Stmt
+	query string
+	conn  *conn
+}
+
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// Exec is deprecated, but it is required by driver.Stmt.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.stmt.Exec(args)
+	report(context.Background(), s.conn.filter, s.conn.callback, s.query, namedValues(args), start, err)
+	return res, err
+}
+
+// Query is deprecated, but it is required by driver.Stmt.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.Query(args)
+	report(context.Background(), s.conn.filter, s.conn.callback, s.query, namedValues(args), start, err)
+	return rows, err
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.execContext(ctx, args)
+	report(ctx, s.conn.filter, s.conn.callback, s.query, args, start, err)
+	return res, err
+}
+
+func (s *stmt) execContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+
+	values, err := values(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stmt.Exec(values)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.queryContext(ctx, args)
+	report(ctx, s.conn.filter, s.conn.callback, s.query, args, start, err)
+	return rows, err
+}
+
+func (s *stmt) queryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+
+	values, err := values(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stmt.Query(values)
+}
+
+func (s *stmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return s.conn.CheckNamedValue(value)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	res := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		res[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return res
+}
+
+// values converts the arguments for the drivers which don't support named arguments.
+func values(args []driver.NamedValue) ([]driver.Value, error) {
+	res := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errNamedArgs
+		}
+
+		res[i] = arg.Value
+	}
+
+	return res, nil
+}
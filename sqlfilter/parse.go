@@ -0,0 +1,332 @@
+package sqlfilter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	operatorToken
+	placeholderToken
+	punctuationToken
+	otherToken
+)
+
+var comparisonKeywords = []string{"LIKE", "ILIKE"}
+
+type token struct {
+	kind tokenKind
+	text string
+	// ordinal is the 1-based position of the positional placeholders.
+	ordinal int
+	// name is the name of the named placeholders.
+	name string
+}
+
+// parsedQuery is the query with filtered literals and comments and the columns which the placeholders are bound to.
+type parsedQuery struct {
+	text             string
+	columnsByOrdinal map[int]string
+	columnsByName    map[string]string
+}
+
+// parseQuery filters the string literals and the comments of the query and finds the columns of the placeholders.
+// The columns are found for comparisons like "email = ?", "email IN (?, ?)" and for INSERT statements with column lists.
+func parseQuery(f filter.PersonalDataFilter, query string) parsedQuery {
+	text, tokens := scanQuery(f, query)
+	res := parsedQuery{text: text, columnsByOrdinal: map[int]string{}, columnsByName: map[string]string{}}
+
+	var insertColumns []string
+	inInsert, inValues, inColumnList := false, false, false
+	valuesDepth, valueIndex := 0, 0
+	inListColumn, inListDepth, depth := "", 0, 0
+
+	for i, t := range tokens {
+		keyword := strings.ToUpper(t.text)
+		switch {
+		case t.kind == identToken && keyword == "INSERT":
+			inInsert, inValues, insertColumns = true, false, nil
+		case t.kind == identToken && inInsert && keyword == "VALUES":
+			inValues, valuesDepth = true, 0
+		case t.kind == identToken && inColumnList:
+			insertColumns = append(insertColumns, t.text)
+		case t.kind == punctuationToken && t.text == "(":
+			depth++
+			if inInsert && !inValues && i > 0 && tokens[i-1].kind == identToken && insertColumns == nil {
+				inColumnList = true
+			}
+
+			if i > 1 && tokens[i-1].kind == identToken && strings.ToUpper(tokens[i-1].text) == "IN" && tokens[i-2].kind == identToken {
+				inListColumn, inListDepth = tokens[i-2].text, depth
+			}
+
+			if inValues {
+				valuesDepth++
+				if valuesDepth == 1 {
+					valueIndex = 0
+				}
+			}
+		case t.kind == punctuationToken && t.text == ")":
+			if depth == inListDepth {
+				inListColumn, inListDepth = "", 0
+			}
+
+			depth--
+			inColumnList = false
+			if inValues {
+				valuesDepth--
+			}
+		case t.kind == punctuationToken && t.text == "," && inValues && valuesDepth == 1:
+			valueIndex++
+		case t.kind == placeholderToken:
+			column := comparedColumn(tokens, i)
+			if column == "" && inListColumn != "" && depth == inListDepth {
+				column = inListColumn
+			}
+
+			if column == "" && inValues && valuesDepth == 1 && valueIndex < len(insertColumns) {
+				column = insertColumns[valueIndex]
+			}
+
+			if column == "" {
+				continue
+			}
+
+			if t.name != "" {
+				res.columnsByName[t.name] = column
+			} else {
+				res.columnsByOrdinal[t.ordinal] = column
+			}
+		}
+	}
+
+	return res
+}
+
+// comparedColumn returns the column which the placeholder is compared with, e.g. email in "email = ?".
+func comparedColumn(tokens []token, i int) string {
+	if i < 2 || tokens[i-2].kind != identToken {
+		return ""
+	}
+
+	operator := tokens[i-1]
+	if operator.kind == operatorToken || (operator.kind == identToken && indexOf(comparisonKeywords, strings.ToUpper(operator.text)) >= 0) {
+		return tokens[i-2].text
+	}
+
+	return ""
+}
+
+// scanQuery returns the query with filtered string literals and comments and its tokens. MySQL escapes the quotes
+// in the literals with backslashes, while the standard SQL and PostgreSQL don't, except in E'...' strings. The query
+// is scanned with the backslash escapes. When the parser loses its place, e.g. because of unterminated literal or
+// because the backslash escapes were wrong, the rest of the statement is replaced with the mask.
+func scanQuery(f filter.PersonalDataFilter, query string) (string, []token) {
+	var text strings.Builder
+	tokens := []token{}
+	positional := 0
+	// ambiguous is the place of the first literal whose end depends on the backslash escapes.
+	var ambiguous *scanPlace
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		next := byte(0)
+		if i+1 < len(query) {
+			next = query[i+1]
+		}
+
+		start := i
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end, escapedQuote := literalEnd(query, i+1, c, c != '`')
+			if end < 0 {
+				return lostPlace(f, text.String(), tokens, ambiguous, scanPlace{text: text.Len(), tokens: len(tokens), quote: string(c)})
+			}
+
+			if escapedQuote && ambiguous == nil && !isEscapeString(query, i) {
+				ambiguous = &scanPlace{text: text.Len(), tokens: len(tokens), quote: string(c)}
+			}
+
+			if c == '\'' {
+				tokens = append(tokens, token{kind: otherToken})
+			} else {
+				tokens = append(tokens, token{kind: identToken, text: query[i+1 : end]})
+			}
+
+			// The double quotes are string literals in MySQL, so the identifiers are filtered too.
+			text.WriteString(string(c) + filter.FilterString(f, query[i+1:end]) + string(c))
+			i = end + 1
+			continue
+		case c == '-' && next == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+
+			text.WriteString(filter.FilterString(f, query[i:i+end]))
+			i += end
+			continue
+		case c == '/' && next == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return lostPlace(f, text.String(), tokens, ambiguous, scanPlace{text: text.Len(), tokens: len(tokens), quote: "/*"})
+			}
+
+			end += i + 4
+			text.WriteString(filter.FilterString(f, query[i:end]))
+			i = end
+			continue
+		case c == '?':
+			positional++
+			tokens = append(tokens, token{kind: placeholderToken, ordinal: positional})
+			i++
+		case c == '$' && isDigit(next):
+			i++
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+
+			ordinal, _ := strconv.Atoi(query[start+1 : i])
+			tokens = append(tokens, token{kind: placeholderToken, ordinal: ordinal})
+		case c == '$' && dollarTag(query[i:]) != "":
+			// PostgreSQL dollar-quoted string, e.g. $$text$$ or $tag$text$tag$.
+			tag := dollarTag(query[i:])
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				return lostPlace(f, text.String(), tokens, ambiguous, scanPlace{text: text.Len(), tokens: len(tokens), quote: tag})
+			}
+
+			end += i + len(tag)
+			tokens = append(tokens, token{kind: otherToken})
+			text.WriteString(tag + filter.FilterString(f, query[i+len(tag):end]) + tag)
+			i = end + len(tag)
+			continue
+		case (c == ':' || c == '@') && isIdentStart(next) && (i == 0 || query[i-1] != c):
+			i++
+			for i < len(query) && isIdentPart(query[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: placeholderToken, name: query[start+1 : i]})
+		case isIdentStart(c):
+			for i < len(query) && (isIdentPart(query[i]) || query[i] == '.') {
+				i++
+			}
+
+			// The qualified names are bound to their last part, e.g. u.email to email.
+			name := query[start:i]
+			tokens = append(tokens, token{kind: identToken, text: name[strings.LastIndexByte(name, '.')+1:]})
+		case strings.IndexByte("<>=!~", c) >= 0:
+			for i < len(query) && strings.IndexByte("<>=!~", query[i]) >= 0 {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: operatorToken, text: query[start:i]})
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, token{kind: punctuationToken, text: string(c)})
+			i++
+		case unicode.IsSpace(rune(c)):
+			i++
+		default:
+			tokens = append(tokens, token{kind: otherToken, text: string(c)})
+			i++
+		}
+
+		text.WriteString(query[start:i])
+	}
+
+	return text.String(), tokens
+}
+
+// scanPlace is a place in the scanned query. It contains the length of the scanned text and the number of the tokens
+// before the place and the quote which starts there.
+type scanPlace struct {
+	text   int
+	tokens int
+	quote  string
+}
+
+// lostPlace returns the scanned text and tokens when the parser loses its place. The rest of the statement after the
+// first ambiguous literal or after the lost place is replaced with the mask, because it may be part of some literal.
+func lostPlace(f filter.PersonalDataFilter, text string, tokens []token, ambiguous *scanPlace, lost scanPlace) (string, []token) {
+	if ambiguous != nil {
+		lost = *ambiguous
+	}
+
+	return text[:lost.text] + lost.quote + filter.Mask(f), tokens[:lost.tokens]
+}
+
+// literalEnd returns the index of the quote which closes the literal or -1 when the literal is not terminated.
+// The doubled quotes are escaped quotes. The backslashes escape the next character when backslashEscapes is set.
+// escapedQuote reports whether some quote was escaped with backslash.
+func literalEnd(query string, i int, quote byte, backslashEscapes bool) (end int, escapedQuote bool) {
+	for i < len(query) {
+		switch {
+		case backslashEscapes && query[i] == '\\':
+			if i+1 < len(query) && query[i+1] == quote {
+				escapedQuote = true
+			}
+
+			i += 2
+		case query[i] == quote && i+1 < len(query) && query[i+1] == quote:
+			i += 2
+		case query[i] == quote:
+			return i, escapedQuote
+		default:
+			i++
+		}
+	}
+
+	return -1, escapedQuote
+}
+
+// isEscapeString checks if the quote at the provided index starts PostgreSQL escape string, e.g. E'it\'s'.
+func isEscapeString(query string, i int) bool {
+	if query[i] != '\'' || i == 0 || (query[i-1] != 'E' && query[i-1] != 'e') {
+		return false
+	}
+
+	return i == 1 || !(isIdentPart(query[i-2]) || query[i-2] == '.')
+}
+
+// dollarTag returns the tag which starts the dollar-quoted string, e.g. $$ or $body$. It is empty when there is no tag.
+func dollarTag(query string) string {
+	i := 1
+	for i < len(query) && (isIdentStart(query[i]) || (i > 1 && isDigit(query[i]))) {
+		i++
+	}
+
+	if i < len(query) && query[i] == '$' {
+		return query[:i+1]
+	}
+
+	return ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
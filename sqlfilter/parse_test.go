@@ -0,0 +1,74 @@
+package sqlfilter
+
+import (
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseQuery(t *testing.T) {
+	Convey("parseQuery", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should filter the string literals and the comments", func() {
+			parsed := parseQuery(f, "SELECT * FROM users WHERE note = 'from "+email+"' AND name = 'O''Brien' -- "+ip+"\n/* "+email+" */")
+			So(parsed.text, ShouldEqual, "SELECT * FROM users WHERE note = 'from *****' AND name = 'O''Brien' -- *****\n/* ***** */")
+		})
+
+		Convey("Should mask the rest of the statement after the unterminated literals", func() {
+			So(parseQuery(f, "SELECT '"+email).text, ShouldEqual, "SELECT '*****")
+			So(parseQuery(f, `SELECT "email`).text, ShouldEqual, `SELECT "*****`)
+			So(parseQuery(f, "SELECT 1 /* "+email).text, ShouldEqual, "SELECT 1 /******")
+			So(parseQuery(f, "SELECT $body$ "+email).text, ShouldEqual, "SELECT $body$*****")
+		})
+
+		Convey("Should filter the literals with backslash escapes", func() {
+			parsed := parseQuery(f, `SELECT * FROM notes WHERE note = 'it\'s `+email+` here' AND path = 'C:\\' AND id = ?`)
+			So(parsed.text, ShouldEqual, `SELECT * FROM notes WHERE note = 'it\'s ***** here' AND path = 'C:\\' AND id = ?`)
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "id"})
+
+			parsed = parseQuery(f, `SELECT * FROM notes WHERE note = E'it\'s `+email+`' AND id = $1`)
+			So(parsed.text, ShouldEqual, `SELECT * FROM notes WHERE note = E'it\'s *****' AND id = $1`)
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "id"})
+
+			So(parseQuery(f, `SELECT "it\"s `+email+`"`).text, ShouldEqual, `SELECT "it\"s *****"`)
+		})
+
+		Convey("Should mask the rest of the statement when the backslash escapes lose the place of the parser", func() {
+			parsed := parseQuery(f, `SELECT * FROM files WHERE path = 'C:\' AND owner = '`+email+`' AND id = ?`)
+			So(parsed.text, ShouldEqual, `SELECT * FROM files WHERE path = '*****`)
+			So(parsed.columnsByOrdinal, ShouldBeEmpty)
+
+			So(parseQuery(f, `SELECT 'it\'s `+email).text, ShouldEqual, `SELECT '*****`)
+		})
+
+		Convey("Should filter the dollar-quoted strings", func() {
+			parsed := parseQuery(f, "SELECT $$"+email+"$$, $body$ from "+ip+" $$ $body$, price$1 FROM t WHERE id = $1")
+			So(parsed.text, ShouldEqual, "SELECT $$*****$$, $body$ from ***** $$ $body$, price$1 FROM t WHERE id = $1")
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "id"})
+		})
+
+		Convey("Should find the columns of the comparisons", func() {
+			parsed := parseQuery(f, `SELECT * FROM users u WHERE u.email = ? AND "name" LIKE ? AND age > ? AND note = 'a?' AND ip IN (?, ?)`)
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "email", 2: "name", 3: "age", 4: "ip", 5: "ip"})
+		})
+
+		Convey("Should find the columns of the numbered and the named placeholders", func() {
+			parsed := parseQuery(f, "UPDATE users SET email = $2, note = :note WHERE id = $1 AND created::date = @day")
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "id", 2: "email"})
+			So(parsed.columnsByName, ShouldResemble, map[string]string{"note": "note", "day": "date"})
+		})
+
+		Convey("Should find the columns of INSERT statements", func() {
+			parsed := parseQuery(f, "INSERT INTO users (`id`, user_email, created) VALUES (?, ?, now()), (?, lower(?), ?)")
+			So(parsed.columnsByOrdinal, ShouldResemble, map[int]string{1: "id", 2: "user_email", 3: "id", 5: "created"})
+		})
+
+		Convey("Should not find columns of INSERT statements without column lists", func() {
+			parsed := parseQuery(f, "INSERT INTO users VALUES (?, ?)")
+			So(parsed.columnsByOrdinal, ShouldBeEmpty)
+		})
+	})
+}
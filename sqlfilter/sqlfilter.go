@@ -0,0 +1,127 @@
+// Package sqlfilter wraps database/sql drivers, so the executed statements can be logged without personal data.
+// The statements are passed to the database as they are. Only the copies which are passed to the callback are filtered.
+package sqlfilter
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+)
+
+// Query is the filtered statement which was executed.
+type Query struct {
+	// Query is the text of the statement. The string literals and the comments are filtered with the regular expressions.
+	Query string
+	// Args are the filtered arguments. The text values of the named arguments with personal data property names
+	// and of the placeholders which are bound to personal data property columns are replaced with the mask.
+	// All other text values are filtered with the regular expressions.
+	Args []driver.NamedValue
+	// Error is the filtered text of the execution error.
+	Error    string
+	Duration time.Duration
+}
+
+// Callback receives the executed statements. The context is the one of the execution.
+type Callback func(ctx context.Context, query Query)
+
+type wrappedDriver struct {
+	driver   driver.Driver
+	filter   filter.PersonalDataFilter
+	callback Callback
+}
+
+// Wrap returns driver which passes the filtered statements executed with the connections of the provided driver
+// to the callback. The returned driver can be registered with sql.Register.
+func Wrap(d driver.Driver, f filter.PersonalDataFilter, callback Callback) driver.Driver {
+	return &wrappedDriver{driver: d, filter: f, callback: callback}
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{conn: c, filter: d.filter, callback: d.callback}, nil
+}
+
+type wrappedConnector struct {
+	connector driver.Connector
+	driver    *wrappedDriver
+}
+
+// WrapConnector returns connector which passes the filtered statements executed with its connections to the callback.
+// The returned connector can be used with sql.OpenDB.
+func WrapConnector(c driver.Connector, f filter.PersonalDataFilter, callback Callback) driver.Connector {
+	return &wrappedConnector{connector: c, driver: &wrappedDriver{driver: c.Driver(), filter: f, callback: callback}}
+}
+
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{conn: inner, filter: c.driver.filter, callback: c.driver.callback}, nil
+}
+
+func (c *wrappedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// report passes the filtered statement to the callback.
+func report(ctx context.Context, f filter.PersonalDataFilter, callback Callback, query string, args []driver.NamedValue, start time.Time, err error) {
+	parsed := parseQuery(f, query)
+	filtered := Query{
+		Query:    parsed.text,
+		Args:     filterArgs(f, parsed, args),
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		filtered.Error = filter.FilterString(f, err.Error())
+	}
+
+	callback(ctx, filtered)
+}
+
+func filterArgs(f filter.PersonalDataFilter, parsed parsedQuery, args []driver.NamedValue) []driver.NamedValue {
+	res := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		column := parsed.columnsByOrdinal[arg.Ordinal]
+		if arg.Name != "" {
+			column = parsed.columnsByName[arg.Name]
+		}
+
+		personal := isPersonalDataColumn(f, arg.Name) || isPersonalDataColumn(f, column)
+		res[i] = arg
+		switch value := arg.Value.(type) {
+		case string:
+			res[i].Value = filterText(f, value, personal)
+		case []byte:
+			res[i].Value = []byte(filterText(f, string(value), personal))
+		}
+	}
+
+	return res
+}
+
+func filterText(f filter.PersonalDataFilter, value string, personal bool) string {
+	if personal {
+		return filter.Mask(f)
+	}
+
+	return filter.FilterString(f, value)
+}
+
+// isPersonalDataColumn checks the name as it is and without underscores, e.g. user_email is checked as useremail too.
+func isPersonalDataColumn(f filter.PersonalDataFilter, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	return filter.IsPersonalDataProperty(f, name) || filter.IsPersonalDataProperty(f, strings.Replace(name, "_", "", -1))
+}
@@ -0,0 +1,231 @@
+package sqlfilter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+type executed struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// fakeDriver records the executed statements. The legacy connections support only prepared statements.
+type fakeDriver struct {
+	legacy   bool
+	executed []executed
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	if d.legacy {
+		return &fakeLegacyConn{driver: d}, nil
+	}
+
+	return &fakeConn{fakeLegacyConn{driver: d}}, nil
+}
+
+func (d *fakeDriver) record(query string, args []driver.NamedValue) error {
+	d.executed = append(d.executed, executed{query: query, args: args})
+	if len(args) > 0 && args[0].Value == "fail" {
+		return errors.New("invalid " + email)
+	}
+
+	return nil
+}
+
+type fakeLegacyConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeLegacyConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *fakeLegacyConn) Close() error {
+	return nil
+}
+
+func (c *fakeLegacyConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeConn struct {
+	fakeLegacyConn
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.record(query, args); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.driver.record(query, args); err != nil {
+		return nil, err
+	}
+
+	return fakeRows{}, nil
+}
+
+type fakeStmt struct {
+	driver *fakeDriver
+	query  string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.driver.record(s.query, namedValues(args)); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.driver.record(s.query, namedValues(args)); err != nil {
+		return nil, err
+	}
+
+	return fakeRows{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+type fakeConnector struct {
+	driver *fakeDriver
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestWrap(t *testing.T) {
+	Convey("Wrap", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		queries := []Query{}
+		callback := func(ctx context.Context, query Query) {
+			queries = append(queries, query)
+		}
+
+		for _, legacy := range []bool{false, true} {
+			fake := &fakeDriver{legacy: legacy}
+			db := sql.OpenDB(WrapConnector(fakeConnector{driver: fake}, f, callback))
+
+			Convey("Should pass the filtered statements to the callback without changing the executed ones, legacy: "+map[bool]string{false: "false", true: "true"}[legacy], func() {
+				query := "INSERT INTO users (email, note, age) VALUES (?, ?, ?) -- from " + email
+				_, err := db.Exec(query, "not-personal", "ip "+ip, 42)
+				So(err, ShouldBeNil)
+
+				So(fake.executed, ShouldHaveLength, 1)
+				So(fake.executed[0].query, ShouldEqual, query)
+				So(fake.executed[0].args[0].Value, ShouldEqual, "not-personal")
+				So(fake.executed[0].args[1].Value, ShouldEqual, "ip "+ip)
+
+				So(queries, ShouldHaveLength, 1)
+				So(queries[0].Query, ShouldEqual, "INSERT INTO users (email, note, age) VALUES (?, ?, ?) -- from *****")
+				So(queries[0].Args, ShouldResemble, []driver.NamedValue{
+					{Ordinal: 1, Value: filteredString},
+					{Ordinal: 2, Value: "ip " + filteredString},
+					{Ordinal: 3, Value: int64(42)},
+				})
+				So(queries[0].Error, ShouldBeEmpty)
+			})
+
+			Convey("Should filter the arguments of queries and the errors, legacy: "+map[bool]string{false: "false", true: "true"}[legacy], func() {
+				rows, err := db.Query("SELECT id FROM users WHERE password = ?", []byte("not-personal"))
+				So(err, ShouldBeNil)
+				So(rows.Close(), ShouldBeNil)
+
+				_, err = db.Exec("UPDATE users SET note = ?", "fail")
+				So(err, ShouldNotBeNil)
+
+				So(queries, ShouldHaveLength, 2)
+				So(queries[0].Args[0].Value, ShouldResemble, []byte(filteredString))
+				So(queries[1].Error, ShouldEqual, "invalid "+filteredString)
+			})
+		}
+
+		Convey("Should filter the named arguments by name", func() {
+			fake := &fakeDriver{}
+			sql.Register("pdfilter-fake", Wrap(fake, f, callback))
+			db, err := sql.Open("pdfilter-fake", "")
+			So(err, ShouldBeNil)
+
+			_, err = db.Exec("UPDATE users SET note = @userEmail WHERE id = @id", sql.Named("userEmail", "not-personal"), sql.Named("id", "user "+email))
+			So(err, ShouldBeNil)
+
+			So(fake.executed[0].args[0].Value, ShouldEqual, "not-personal")
+			So(queries[0].Args, ShouldResemble, []driver.NamedValue{
+				{Name: "userEmail", Ordinal: 1, Value: filteredString},
+				{Name: "id", Ordinal: 2, Value: "user " + filteredString},
+			})
+
+			tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+			So(err, ShouldBeNil)
+			So(tx.Rollback(), ShouldBeNil)
+		})
+
+		Convey("Should not support named arguments and transaction options for legacy drivers", func() {
+			db := sql.OpenDB(WrapConnector(fakeConnector{driver: &fakeDriver{legacy: true}}, f, callback))
+			_, err := db.Exec("UPDATE users SET note = @note", sql.Named("note", "text"))
+			So(err, ShouldNotBeNil)
+
+			_, err = db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
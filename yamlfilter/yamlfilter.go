@@ -0,0 +1,150 @@
+// Package yamlfilter removes personal data from YAML documents. The documents are filtered
+// as node trees, so the comments, the anchors and the key order are preserved.
+package yamlfilter
+
+import (
+	"io"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	stringTag = "!!str"
+	indent    = 2
+)
+
+// FilterYAML reads the YAML documents from the reader and writes them filtered to the writer.
+func FilterYAML(f filter.PersonalDataFilter, r io.Reader, w io.Writer) error {
+	decoder := yaml.NewDecoder(r)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(indent)
+
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		FilterNode(f, &document)
+		if err := encoder.Encode(&document); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
+
+// FilterNode filters the node tree in place. The mapping keys are used as property names. The values
+// of the personal data properties are replaced with the mask. All other scalars and the comments
+// are filtered with the regular expressions. The mapping keys are filtered only when FilterMapKeys is used.
+// The keys which collide after filtering are handled with its policy, so the output is valid YAML.
+func FilterNode(f filter.PersonalDataFilter, node *yaml.Node) {
+	filterNode(f, node, "", map[*yaml.Node]bool{})
+}
+
+func filterNode(f filter.PersonalDataFilter, node *yaml.Node, property string, visited map[*yaml.Node]bool) {
+	// The anchored nodes are filtered only once.
+	if visited[node] {
+		return
+	}
+
+	visited[node] = true
+
+	filterComments(f, node)
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			filterNode(f, child, "", visited)
+		}
+	case yaml.MappingNode:
+		filterMapping(f, node, visited)
+	case yaml.ScalarNode:
+		filterScalar(f, node, property)
+	}
+}
+
+func filterMapping(f filter.PersonalDataFilter, node *yaml.Node, visited map[*yaml.Node]bool) {
+	keys := filterKeys(f, node, visited)
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		// The original key is used as property name, because the filtered key may be the mask.
+		property := key.Value
+		if key.Kind == yaml.ScalarNode {
+			filtered, ok := keys[key.Value]
+			if !ok {
+				// The filtered key collides with another one.
+				continue
+			}
+
+			setScalar(key, filtered)
+		}
+
+		// The anchored node may be defined under a property which is not personal data.
+		// The alias is replaced with the masked value, otherwise the value will be leaked.
+		if value.Kind == yaml.AliasNode && isString(value.Alias) && filter.IsPersonalDataProperty(f, property) {
+			masked := *value.Alias
+			masked.Anchor = ""
+			value = &masked
+		}
+
+		filterNode(f, value, property, visited)
+		content = append(content, key, value)
+	}
+
+	node.Content = content
+}
+
+// filterKeys returns the filtered values of the scalar keys of the mapping node. The keys are filtered only
+// when FilterMapKeys is used and the collisions are resolved with its policy. The other keys are filtered as nodes.
+func filterKeys(f filter.PersonalDataFilter, node *yaml.Node, visited map[*yaml.Node]bool) map[string]string {
+	keys := []string{}
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Kind != yaml.ScalarNode {
+			filterNode(f, key, "", visited)
+			continue
+		}
+
+		// The keys are not filtered as values, even when they are anchored.
+		visited[key] = true
+		filterComments(f, key)
+		keys = append(keys, key.Value)
+	}
+
+	return filter.FilterKeys(f, keys)
+}
+
+func filterScalar(f filter.PersonalDataFilter, node *yaml.Node, property string) {
+	if isString(node) {
+		node.Value = filter.FilterProperty(f, property, node.Value)
+		return
+	}
+
+	// The regular expressions may match values of other types, e.g. IP addresses which look like floats.
+	setScalar(node, filter.FilterString(f, node.Value))
+}
+
+// setScalar sets the filtered value of the scalar. The changed values of other types become strings.
+func setScalar(node *yaml.Node, filtered string) {
+	if filtered != node.Value {
+		node.Value = filtered
+		node.Tag = stringTag
+	}
+}
+
+func filterComments(f filter.PersonalDataFilter, node *yaml.Node) {
+	node.HeadComment = filter.FilterString(f, node.HeadComment)
+	node.LineComment = filter.FilterString(f, node.LineComment)
+	node.FootComment = filter.FilterString(f, node.FootComment)
+}
+
+func isString(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == stringTag
+}
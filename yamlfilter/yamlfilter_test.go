@@ -0,0 +1,104 @@
+package yamlfilter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFilterYAML(t *testing.T) {
+	Convey("FilterYAML", t, func() {
+		f, err := filter.NewBuilder().SetMask("*****").Build()
+		So(err, ShouldBeNil)
+
+		Convey("Should filter the documents and keep their structure", func() {
+			input := `# owner: some@mail.com
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app # contact admin@mail.com
+  labels:
+    email: not-personal
+data:
+  secret: &secret not-personal
+  password: *secret
+  other: *secret
+  userid: 42
+  address: 192.168.0.1
+  servers:
+    - 10.0.0.1
+    - name: main
+      user: admin
+---
+email: not-personal
+`
+			expected := `# owner: *****
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app # contact *****
+  labels:
+    email: '*****'
+data:
+  secret: &secret not-personal
+  password: '*****'
+  other: *secret
+  userid: 42
+  address: '*****'
+  servers:
+    - '*****'
+    - name: main
+      user: '*****'
+---
+email: '*****'
+`
+			output := new(bytes.Buffer)
+			err := FilterYAML(f, strings.NewReader(input), output)
+
+			So(err, ShouldBeNil)
+			So(output.String(), ShouldEqual, expected)
+		})
+
+		Convey("Should filter the keys only when the map keys filtering is enabled", func() {
+			input := "alice@x.com: 1\nbob@x.com: 2\nname: main\n"
+			expected := map[filter.MapKeyCollisionPolicy]string{
+				filter.MapKeyCollisionSuffix:    "'*****': 1\n'*****-2': 2\nname: main\n",
+				filter.MapKeyCollisionKeepFirst: "'*****': 1\nname: main\n",
+			}
+
+			output := new(bytes.Buffer)
+			So(FilterYAML(f, strings.NewReader(input), output), ShouldBeNil)
+			So(output.String(), ShouldEqual, input)
+
+			for policy, result := range expected {
+				keysFilter, err := filter.NewBuilder().SetMask("*****").FilterMapKeys(policy).Build()
+				So(err, ShouldBeNil)
+
+				output := new(bytes.Buffer)
+				So(FilterYAML(keysFilter, strings.NewReader(input), output), ShouldBeNil)
+				So(output.String(), ShouldEqual, result)
+
+				var decoded map[string]interface{}
+				So(yaml.Unmarshal(output.Bytes(), &decoded), ShouldBeNil)
+			}
+		})
+
+		Convey("Should check the original keys against the personal data properties", func() {
+			keysFilter, err := filter.NewBuilder().SetMask("*****").AddPersonalDataProperties("admin@x.com").FilterMapKeys(filter.MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			output := new(bytes.Buffer)
+			So(FilterYAML(keysFilter, strings.NewReader("admin@x.com: not-personal\n"), output), ShouldBeNil)
+			So(output.String(), ShouldEqual, "'*****': '*****'\n")
+		})
+
+		Convey("Should return error for invalid YAML", func() {
+			err := FilterYAML(f, strings.NewReader("key: [value"), new(bytes.Buffer))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package zapfilter
+
+import (
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"go.uber.org/zap/zapcore"
+)
+
+// objectMarshaler filters the values which the wrapped marshaler adds to the encoder.
+type objectMarshaler struct {
+	filter    filter.PersonalDataFilter
+	marshaler zapcore.ObjectMarshaler
+}
+
+func (m objectMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return m.marshaler.MarshalLogObject(&objectEncoder{ObjectEncoder: enc, filter: m.filter})
+}
+
+// arrayMarshaler filters the values which the wrapped marshaler appends to the encoder.
+type arrayMarshaler struct {
+	filter    filter.PersonalDataFilter
+	marshaler zapcore.ArrayMarshaler
+}
+
+func (m arrayMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return m.marshaler.MarshalLogArray(&arrayEncoder{ArrayEncoder: enc, filter: m.filter})
+}
+
+// objectEncoder filters the text and the reflected values. The keys are used as property names.
+type objectEncoder struct {
+	zapcore.ObjectEncoder
+	filter filter.PersonalDataFilter
+}
+
+func (enc *objectEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	return enc.ObjectEncoder.AddArray(key, arrayMarshaler{filter: enc.filter, marshaler: marshaler})
+}
+
+func (enc *objectEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	return enc.ObjectEncoder.AddObject(key, objectMarshaler{filter: enc.filter, marshaler: marshaler})
+}
+
+func (enc *objectEncoder) AddBinary(key string, value []byte) {
+	enc.ObjectEncoder.AddBinary(key, []byte(filter.FilterProperty(enc.filter, key, string(value))))
+}
+
+func (enc *objectEncoder) AddByteString(key string, value []byte) {
+	enc.ObjectEncoder.AddByteString(key, []byte(filter.FilterProperty(enc.filter, key, string(value))))
+}
+
+func (enc *objectEncoder) AddString(key, value string) {
+	enc.ObjectEncoder.AddString(key, filter.FilterProperty(enc.filter, key, value))
+}
+
+func (enc *objectEncoder) AddReflected(key string, value interface{}) error {
+	return enc.ObjectEncoder.AddReflected(key, filter.FilterPropertyValue(enc.filter, key, value))
+}
+
+// arrayEncoder filters the text and the reflected values. The elements of the arrays have no property names.
+type arrayEncoder struct {
+	zapcore.ArrayEncoder
+	filter filter.PersonalDataFilter
+}
+
+func (enc *arrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return enc.ArrayEncoder.AppendArray(arrayMarshaler{filter: enc.filter, marshaler: marshaler})
+}
+
+func (enc *arrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return enc.ArrayEncoder.AppendObject(objectMarshaler{filter: enc.filter, marshaler: marshaler})
+}
+
+func (enc *arrayEncoder) AppendByteString(value []byte) {
+	enc.ArrayEncoder.AppendByteString([]byte(filter.FilterString(enc.filter, string(value))))
+}
+
+func (enc *arrayEncoder) AppendString(value string) {
+	enc.ArrayEncoder.AppendString(filter.FilterString(enc.filter, value))
+}
+
+func (enc *arrayEncoder) AppendReflected(value interface{}) error {
+	return enc.ArrayEncoder.AppendReflected(enc.filter.RemovePersonalData(value))
+}
@@ -0,0 +1,146 @@
+// Package zapfilter removes personal data from the entries logged with zap.
+package zapfilter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"go.uber.org/zap/zapcore"
+)
+
+type core struct {
+	filter filter.PersonalDataFilter
+	core   zapcore.Core
+}
+
+// NewCore returns core which filters the messages and the fields and passes them to the wrapped core.
+// The fields are filtered only for the entries with enabled levels.
+func NewCore(f filter.PersonalDataFilter, c zapcore.Core) zapcore.Core {
+	return &core{filter: f, core: c}
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.core.Enabled(level)
+}
+
+// Level returns the minimum enabled level of the wrapped core.
+func (c *core) Level() zapcore.Level {
+	return zapcore.LevelOf(c.core)
+}
+
+// With filters the fields once. They are not filtered again when the entries are written.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{filter: c.filter, core: c.core.With(FilterFields(c.filter, fields))}
+}
+
+// Check delegates to the wrapped core, so its level routing and sampling are respected. The cores added
+// by the wrapped core are written through the filtering core, so the entries can't be written without filtering.
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.core.Check(entry, nil)
+	if inner == nil {
+		return checked
+	}
+
+	return checked.AddCore(entry, &checkedCore{core: c, checked: inner})
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = filter.FilterString(c.filter, entry.Message)
+	return c.core.Write(entry, FilterFields(c.filter, fields))
+}
+
+func (c *core) Sync() error {
+	return c.core.Sync()
+}
+
+// checkedCore writes the filtered entries to the cores of the entry checked by the wrapped core.
+type checkedCore struct {
+	*core
+	checked *zapcore.CheckedEntry
+}
+
+func (c *checkedCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	// The checked entry reports the write errors to its error output instead of returning them.
+	errorOutput := new(errorOutput)
+	entry.Message = filter.FilterString(c.filter, entry.Message)
+	c.checked.Entry = entry
+	c.checked.ErrorOutput = errorOutput
+	c.checked.Write(FilterFields(c.filter, fields)...)
+	if errorOutput.Len() > 0 {
+		return errors.New(strings.TrimSpace(errorOutput.String()))
+	}
+
+	return nil
+}
+
+type errorOutput struct {
+	bytes.Buffer
+}
+
+func (o *errorOutput) Sync() error {
+	return nil
+}
+
+// FilterFields returns the filtered fields. The provided slice is not modified.
+func FilterFields(f filter.PersonalDataFilter, fields []zapcore.Field) []zapcore.Field {
+	res := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		res[i] = FilterField(f, field)
+	}
+
+	return res
+}
+
+// FilterField filters the field. The key is used as property name. The objects and the arrays
+// are filtered when they are encoded, so the nested keys are used as property names too.
+func FilterField(f filter.PersonalDataFilter, field zapcore.Field) zapcore.Field {
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = filter.FilterProperty(f, field.Key, field.String)
+	case zapcore.ByteStringType, zapcore.BinaryType:
+		if value, ok := field.Interface.([]byte); ok {
+			field.Interface = []byte(filter.FilterProperty(f, field.Key, string(value)))
+		}
+	case zapcore.StringerType:
+		if text, ok := stringerText(field.Interface); ok {
+			field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: filter.FilterProperty(f, field.Key, text)}
+		}
+	case zapcore.ErrorType:
+		// The verbose form of the error can't be filtered, so only the message is kept.
+		if err, ok := field.Interface.(error); ok {
+			field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: filter.FilterProperty(f, field.Key, err.Error())}
+		}
+	case zapcore.ReflectType:
+		field.Interface = filter.FilterPropertyValue(f, field.Key, field.Interface)
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		if marshaler, ok := field.Interface.(zapcore.ObjectMarshaler); ok {
+			field.Interface = objectMarshaler{filter: f, marshaler: marshaler}
+		}
+	case zapcore.ArrayMarshalerType:
+		if marshaler, ok := field.Interface.(zapcore.ArrayMarshaler); ok {
+			field.Interface = arrayMarshaler{filter: f, marshaler: marshaler}
+		}
+	}
+
+	return field
+}
+
+// stringerText returns the text of the stringer. The stringers may panic, e.g. when they are nil pointers.
+// The field is kept as it is in this case, so zap can report the panic.
+func stringerText(stringer interface{}) (text string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	s, ok := stringer.(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+
+	return s.String(), true
+}
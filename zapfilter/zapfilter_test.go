@@ -0,0 +1,197 @@
+package zapfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+type address struct {
+	street  string
+	account string
+}
+
+func (a address) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("street", a.street)
+	enc.AddString("account", a.account)
+	return enc.AddArray("notes", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString("from " + email)
+		return enc.AppendReflected(user{Name: "John", Email: "not-personal"})
+	}))
+}
+
+type countingStringer struct {
+	count *int
+}
+
+func (s countingStringer) String() string {
+	*s.count++
+	return email
+}
+
+// constantFilter is custom filter which replaces every value.
+type constantFilter struct{}
+
+func (constantFilter) RemovePersonalData(interface{}) interface{} {
+	return filteredString
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestCore(t *testing.T) {
+	Convey("Core", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		output := new(bytes.Buffer)
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.TimeKey = ""
+		inner := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(output), zapcore.InfoLevel)
+		logger := zap.New(NewCore(f, inner))
+
+		entry := func() map[string]interface{} {
+			res := map[string]interface{}{}
+			So(json.Unmarshal(output.Bytes(), &res), ShouldBeNil)
+			output.Reset()
+			return res
+		}
+
+		Convey("Should filter the message and the fields", func() {
+			count := 0
+			logger.Info("user "+email+" logged in",
+				zap.String("email", "not-personal"),
+				zap.String("note", "ip "+ip),
+				zap.Int("userId", 42),
+				zap.ByteString("password", []byte("not-personal")),
+				zap.Stringer("contact", countingStringer{count: &count}),
+				zap.Error(errors.New("invalid "+email)),
+				zap.Any("user", user{Name: "John", Email: "not-personal"}),
+				zap.Any("tags", []string{email, "tag"}),
+			)
+
+			So(entry(), ShouldResemble, map[string]interface{}{
+				"level":    "info",
+				"msg":      "user " + filteredString + " logged in",
+				"email":    filteredString,
+				"note":     "ip " + filteredString,
+				"userId":   float64(42),
+				"password": filteredString,
+				"contact":  filteredString,
+				"error":    "invalid " + filteredString,
+				"user":     map[string]interface{}{"Name": "John", "Email": filteredString},
+				"tags":     []interface{}{filteredString, "tag"},
+			})
+		})
+
+		Convey("Should filter the object marshalers", func() {
+			logger.Info("message", zap.Object("address", address{street: email, account: "not-personal"}), zap.Inline(address{street: "street", account: "not-personal"}))
+
+			So(entry(), ShouldResemble, map[string]interface{}{
+				"level": "info",
+				"msg":   "message",
+				"address": map[string]interface{}{
+					"street":  filteredString,
+					"account": filteredString,
+					"notes":   []interface{}{"from " + filteredString, map[string]interface{}{"Name": "John", "Email": filteredString}},
+				},
+				"street":  "street",
+				"account": filteredString,
+				"notes":   []interface{}{"from " + filteredString, map[string]interface{}{"Name": "John", "Email": filteredString}},
+			})
+		})
+
+		Convey("Should filter the fields of With", func() {
+			logger.With(zap.String("ip", "not-personal")).Info("message", zap.String("note", email))
+
+			So(entry(), ShouldResemble, map[string]interface{}{
+				"level": "info",
+				"msg":   "message",
+				"ip":    filteredString,
+				"note":  filteredString,
+			})
+		})
+
+		Convey("Should not filter the fields of disabled levels", func() {
+			count := 0
+			logger.Debug("message", zap.Stringer("contact", countingStringer{count: &count}))
+			So(count, ShouldEqual, 0)
+			So(output.Len(), ShouldEqual, 0)
+			So(logger.Core().Enabled(zapcore.DebugLevel), ShouldBeFalse)
+			So(zapcore.LevelOf(logger.Core()), ShouldEqual, zapcore.InfoLevel)
+		})
+
+		Convey("Should respect the level routing of the wrapped core", func() {
+			errorOutput := new(bytes.Buffer)
+			errorCore := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(errorOutput), zapcore.ErrorLevel)
+			logger := zap.New(NewCore(f, zapcore.NewTee(inner, errorCore)))
+
+			logger.Info("info " + email)
+			So(entry()["msg"], ShouldEqual, "info "+filteredString)
+			So(errorOutput.Len(), ShouldEqual, 0)
+
+			logger.Error("error "+email, zap.String("note", ip))
+			So(entry()["note"], ShouldEqual, filteredString)
+			So(errorOutput.String(), ShouldContainSubstring, `"msg":"error `+filteredString+`","note":"`+filteredString+`"`)
+		})
+
+		Convey("Should respect the sampling of the wrapped core", func() {
+			logger := zap.New(NewCore(f, zapcore.NewSamplerWithOptions(inner, time.Second, 1, 0)))
+			for i := 0; i < 3; i++ {
+				logger.Info("message", zap.String("note", email))
+			}
+
+			So(entry()["note"], ShouldEqual, filteredString)
+		})
+
+		Convey("Should report the write errors of the wrapped core", func() {
+			errorOutput := new(bytes.Buffer)
+			failing := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(failingWriter{}), zapcore.InfoLevel)
+			logger := zap.New(NewCore(f, failing), zap.ErrorOutput(zapcore.AddSync(errorOutput)))
+
+			logger.Info("message")
+			So(errorOutput.String(), ShouldContainSubstring, "write failed")
+		})
+
+		Convey("Should not modify the provided fields", func() {
+			fields := []zapcore.Field{zap.String("email", "not-personal")}
+			So(FilterFields(f, fields)[0].String, ShouldEqual, filteredString)
+			So(fields[0].String, ShouldEqual, "not-personal")
+		})
+
+		Convey("Should keep the reflected values when the key is filtered", func() {
+			keysFilter, err := filter.NewBuilder().SetMask(filteredString).FilterMapKeys(filter.MapKeyCollisionSuffix).Build()
+			So(err, ShouldBeNil)
+
+			fields := FilterFields(keysFilter, []zapcore.Field{zap.Reflect(ip, []string{"x", email})})
+			So(fields[0].Key, ShouldEqual, ip)
+			So(fields[0].Interface, ShouldResemble, []string{"x", filteredString})
+		})
+
+		Convey("Should filter the reflected values with the custom filters", func() {
+			fields := FilterFields(constantFilter{}, []zapcore.Field{zap.Reflect("ids", []int{1})})
+			So(fields[0].Interface, ShouldEqual, filteredString)
+		})
+	})
+}
@@ -0,0 +1,55 @@
+// Package zerologfilter removes personal data from the events logged with zerolog.
+package zerologfilter
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/rs/zerolog"
+)
+
+// Writer filters the events which zerolog writes and passes them to the wrapped writer.
+// The events are filtered as JSON, so their keys are used as property names. The output
+// which is not valid JSON, e.g. the one of zerolog.ConsoleWriter, is filtered as text.
+type Writer struct {
+	filter filter.PersonalDataFilter
+	w      io.Writer
+}
+
+// NewWriter returns writer which filters the events with the provided filter.
+func NewWriter(f filter.PersonalDataFilter, w io.Writer) *Writer {
+	return &Writer{filter: f, w: w}
+}
+
+// Write filters the event. zerolog writes each event with a single call.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.filterEvent(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteLevel passes the level to the wrapped writer if it is zerolog.LevelWriter.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	levelWriter, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+
+	if _, err := levelWriter.WriteLevel(level, w.filterEvent(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *Writer) filterEvent(p []byte) []byte {
+	filtered := new(bytes.Buffer)
+	if err := filter.FilterJSON(w.filter, bytes.NewReader(p), filtered); err != nil {
+		return []byte(filter.FilterString(w.filter, string(p)))
+	}
+
+	return filtered.Bytes()
+}
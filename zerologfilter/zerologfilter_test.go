@@ -0,0 +1,87 @@
+package zerologfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Icenium/go-personal-data-filter/filter"
+	"github.com/rs/zerolog"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	filteredString = "*****"
+	email          = "some@mail.com"
+	ip             = "192.168.0.1"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+type levelWriter struct {
+	bytes.Buffer
+	levels []zerolog.Level
+}
+
+func (w *levelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.levels = append(w.levels, level)
+	return w.Write(p)
+}
+
+func TestWriter(t *testing.T) {
+	Convey("Writer", t, func() {
+		f, err := filter.NewBuilder().SetMask(filteredString).Build()
+		So(err, ShouldBeNil)
+
+		output := new(levelWriter)
+		logger := zerolog.New(NewWriter(f, output))
+
+		event := func() map[string]interface{} {
+			res := map[string]interface{}{}
+			So(json.Unmarshal(output.Bytes(), &res), ShouldBeNil)
+			output.Reset()
+			return res
+		}
+
+		Convey("Should filter the message and the fields", func() {
+			logger.Info().
+				Str("email", "not-personal").
+				Str("note", "ip "+ip).
+				Int("userId", 42).
+				Interface("user", user{Name: "John", Email: "not-personal"}).
+				Err(errors.New("invalid " + email)).
+				Msg("user " + email + " logged in")
+
+			So(output.String(), ShouldEndWith, "}\n")
+			So(output.levels, ShouldResemble, []zerolog.Level{zerolog.InfoLevel})
+			So(event(), ShouldResemble, map[string]interface{}{
+				"level":   "info",
+				"message": "user " + filteredString + " logged in",
+				"email":   filteredString,
+				"note":    "ip " + filteredString,
+				"userId":  float64(42),
+				"user":    map[string]interface{}{"Name": "John", "Email": filteredString},
+				"error":   "invalid " + filteredString,
+			})
+		})
+
+		Convey("Should filter the output which is not JSON as text", func() {
+			writer := NewWriter(f, output)
+			n, err := writer.Write([]byte("user " + email + " {"))
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, len("user "+email+" {"))
+			So(output.String(), ShouldEqual, "user "+filteredString+" {")
+		})
+
+		Convey("Should work with writers which are not level writers", func() {
+			plainOutput := new(bytes.Buffer)
+			plainLogger := zerolog.New(NewWriter(f, plainOutput))
+			plainLogger.Info().Str("ip", "not-personal").Send()
+			So(plainOutput.String(), ShouldEqual, `{"level":"info","ip":"*****"}`+"\n")
+		})
+	})
+}